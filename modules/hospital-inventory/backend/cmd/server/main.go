@@ -5,8 +5,10 @@ import (
 	"hospital-inventory/database"
 	"hospital-inventory/internal/adapters/handlers"
 	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/services"
 	"log"
+	"os"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -23,15 +25,31 @@ func main() {
 	txRepo := repositories.NewGormTransactionRepository(db)
 	indentRepo := repositories.NewGormIndentRepository(db)
 	orderRepo := repositories.NewSupplyOrderRepository(db)
+	categoryRepo := repositories.NewGormCategoryRepository(db)
+	supplierRepo := repositories.NewGormSupplierRepository(db)
+	unitOfWork := repositories.NewGormUnitOfWork(db)
 
 	// 3. Initialize Services
-	inventoryService := services.NewInventoryService(itemRepo, batchRepo, txRepo)
-	indentService := services.NewIndentService(indentRepo, itemRepo, batchRepo, txRepo)
+	inventoryService := services.NewInventoryService(itemRepo, batchRepo, txRepo, categoryRepo, supplierRepo)
+	supplierService := services.NewSupplierService(supplierRepo)
+	categoryService := services.NewCategoryService(categoryRepo)
+	indentService := services.NewIndentService(indentRepo, itemRepo, batchRepo, unitOfWork, indentAllocation())
+	importService := services.NewImportService(itemRepo, batchRepo, unitOfWork)
+	exportService := services.NewExportService(batchRepo, txRepo)
+	orderService := services.NewSupplyOrderService(orderRepo, itemRepo, supplierService, unitOfWork)
+	reorderService := services.NewReorderService(itemRepo, txRepo, orderRepo)
+	receiptService := services.NewGoodsReceiptService(repositories.NewGormGoodsReceiptRepository(db), unitOfWork)
 
 	// 4. Initialize Handlers
 	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
 	indentHandler := handlers.NewIndentHandler(indentService)
-	orderHandler := handlers.NewSupplyOrderHandler(orderRepo)
+	orderHandler := handlers.NewSupplyOrderHandler(orderService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+	receiptHandler := handlers.NewGoodsReceiptHandler(receiptService)
+	reorderHandler := handlers.NewReorderHandler(reorderService)
 
 	// 5. Setup Router
 	r := gin.Default()
@@ -56,10 +74,24 @@ func main() {
 		api.PUT("/items/:id", inventoryHandler.UpdateItem)
 		api.DELETE("/items/:id", inventoryHandler.DeleteItem)
 
+		// Categories
+		api.GET("/categories", categoryHandler.ListCategories)
+		api.GET("/categories/:id", categoryHandler.GetCategory)
+		api.POST("/categories", categoryHandler.CreateCategory)
+		api.PUT("/categories/:id", categoryHandler.UpdateCategory)
+		api.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+
 		api.POST("/batches", inventoryHandler.AddBatch)
 		api.PUT("/batches/:id", inventoryHandler.UpdateBatch)
 		api.DELETE("/batches/:id", inventoryHandler.DeleteBatch)
 
+		// Bulk Import
+		api.POST("/import/items", importHandler.ImportItems)
+
+		// Exports (format=csv|xlsx|json)
+		api.GET("/export/items", exportHandler.ExportItems)
+		api.GET("/export/audit-logs", exportHandler.ExportAuditLogs)
+
 		// Audit Logs
 		api.GET("/audit-logs", inventoryHandler.GetTransactions)
 		// Dashboard Stats
@@ -70,11 +102,28 @@ func main() {
 		api.GET("/indents", indentHandler.ListIndents)
 		api.GET("/indents/:id", indentHandler.GetIndent)
 		api.PUT("/indents/:id/status", indentHandler.ProcessIndent)
+		api.GET("/indents/:id/history", indentHandler.GetHistory)
+		api.PUT("/indents/:id/lines/:lineId/status", indentHandler.ProcessIndentLine)
+		api.GET("/indents/:id/lines/:lineId/picks", indentHandler.GetPickList)
+		api.PUT("/indents/:id/lines/:lineId/picks", indentHandler.UpdatePickList)
+
+		// Suppliers
+		api.GET("/suppliers", supplierHandler.ListSuppliers)
+		api.GET("/suppliers/:id", supplierHandler.GetSupplier)
+		api.POST("/suppliers", supplierHandler.CreateSupplier)
+		api.PUT("/suppliers/:id", supplierHandler.UpdateSupplier)
+		api.DELETE("/suppliers/:id", supplierHandler.DeleteSupplier)
 
 		// Supply Orders
 		api.POST("/orders", orderHandler.CreateOrder)
 		api.GET("/orders", orderHandler.ListOrders)
+		api.GET("/orders/suggestions", reorderHandler.GetSuggestions)
+		api.GET("/orders/:id", orderHandler.GetOrder)
+		api.GET("/orders/:id/pdf", orderHandler.DownloadPDF)
+		api.GET("/orders/:id/history", orderHandler.GetHistory)
 		api.PUT("/orders/:id/status", orderHandler.UpdateStatus)
+		api.POST("/orders/:id/receipts", receiptHandler.ReceiveOrder)
+		api.GET("/orders/:id/receipts", receiptHandler.ListReceipts)
 	}
 
 	fmt.Println("Starting Modular Server on :8080")
@@ -82,3 +131,17 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// indentAllocation reads INDENT_ALLOCATION, the order indents take batches in:
+// FEFO, FIFO or LOCATION:<location>. Defaults to FEFO.
+func indentAllocation() domain.AllocationStrategy {
+	if v := os.Getenv("INDENT_ALLOCATION"); v != "" {
+		strategy, err := domain.ParseAllocationStrategy(v)
+		if err == nil {
+			log.Printf("Allocating indent stock %s", strategy.Name())
+			return strategy
+		}
+		log.Printf("Ignoring invalid INDENT_ALLOCATION %q: %v", v, err)
+	}
+	return domain.FEFO{}
+}
@@ -12,14 +12,26 @@ var DB *gorm.DB
 
 func Connect() {
 	var err error
-	DB, err = gorm.Open(sqlite.Open("/app/data/spammed.db"), &gorm.Config{})
+	DB, err = gorm.Open(sqlite.Open("/app/data/spammed.db?_pragma=busy_timeout(5000)"), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	err = DB.AutoMigrate(&domain.Category{}, &domain.Item{}, &domain.Batch{}, &domain.InventoryTransaction{}, &domain.EmergencyRequest{}, &domain.Indent{}, &domain.SupplyOrder{})
+	err = DB.AutoMigrate(&domain.Category{}, &domain.Supplier{}, &domain.Item{}, &domain.Batch{}, &domain.InventoryTransaction{}, &domain.EmergencyRequest{}, &domain.Indent{}, &domain.IndentLine{}, &domain.IndentStatusChange{}, &domain.IndentReservation{}, &domain.SupplyOrder{}, &domain.SupplyOrderLine{}, &domain.SupplyOrderStatusChange{}, &domain.GoodsReceipt{}, &domain.GoodsReceiptLine{})
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+	if err := migrateSupplyOrderItems(DB); err != nil {
+		log.Fatal("Failed to convert supply order items:", err)
+	}
+	if err := migrateLegacyOrderStatuses(DB); err != nil {
+		log.Fatal("Failed to migrate supply order statuses:", err)
+	}
+	if err := migrateIndentQuantities(DB); err != nil {
+		log.Fatal("Failed to fill indent quantities:", err)
+	}
+	if err := migrateIndentLines(DB); err != nil {
+		log.Fatal("Failed to move indents onto lines:", err)
+	}
 	log.Println("Database connected and migrated successfully")
 }
@@ -0,0 +1,240 @@
+package database
+
+import (
+	"encoding/json"
+	"hospital-inventory/internal/core/domain"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrateSupplyOrderItems turns the JSON blob in supply_orders.items into SupplyOrderLine rows.
+// Orders that already have lines are skipped, so it is safe to run on every start.
+// The items column is left in place so nothing is lost if a row cannot be read.
+func migrateSupplyOrderItems(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("supply_orders", "items") {
+		return nil
+	}
+
+	var orders []struct {
+		ID    uint
+		Items string
+	}
+	err := db.Table("supply_orders").
+		Select("id, items").
+		Where("items IS NOT NULL AND items != ''").
+		Where("NOT EXISTS (SELECT 1 FROM supply_order_lines l WHERE l.supply_order_id = supply_orders.id)").
+		Scan(&orders).Error
+	if err != nil {
+		return err
+	}
+
+	converted := 0
+	for _, o := range orders {
+		legacy, err := domain.ParseLegacyOrderItems(o.Items)
+		if err != nil {
+			log.Printf("Supply order %d: unreadable items, left unconverted: %v", o.ID, err)
+			continue
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Quantities received before lines existed, by item
+			var receivedRows []struct {
+				ItemID   uint
+				Quantity int
+			}
+			err := tx.Table("goods_receipt_lines l").
+				Select("l.item_id, SUM(l.quantity) AS quantity").
+				Joins("JOIN goods_receipts r ON r.id = l.goods_receipt_id").
+				Where("r.supply_order_id = ? AND r.deleted_at IS NULL", o.ID).
+				Group("l.item_id").
+				Scan(&receivedRows).Error
+			if err != nil {
+				return err
+			}
+			received := make(map[uint]int, len(receivedRows))
+			for _, r := range receivedRows {
+				received[r.ItemID] = r.Quantity
+			}
+
+			order := domain.SupplyOrder{}
+			for _, li := range legacy {
+				if li.Quantity <= 0 {
+					continue
+				}
+				line := domain.SupplyOrderLine{
+					SupplyOrderID: o.ID,
+					ItemName:      strings.TrimSpace(li.ItemName),
+					Quantity:      li.Quantity,
+					UnitCost:      li.UnitCost,
+				}
+				if item := resolveLegacyItem(tx, li); item != nil {
+					line.ItemID = item.ID
+					line.ItemName = item.Name
+					take := received[item.ID]
+					if take > line.Quantity {
+						take = line.Quantity
+					}
+					line.ReceivedQuantity = take
+					received[item.ID] -= take
+				}
+				order.Lines = append(order.Lines, line)
+			}
+			if len(order.Lines) == 0 {
+				return nil
+			}
+			order.ComputeTotals()
+			converted++
+
+			if err := tx.Create(&order.Lines).Error; err != nil {
+				return err
+			}
+			return tx.Table("supply_orders").Where("id = ?", o.ID).Updates(map[string]interface{}{
+				"subtotal":   order.Subtotal,
+				"tax_total":  order.TaxTotal,
+				"total_cost": order.TotalCost,
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if converted > 0 {
+		log.Printf("Converted items of %d supply orders into order lines", converted)
+	}
+	return nil
+}
+
+// migrateLegacyOrderStatuses moves orders still marked Pending, from before the order lifecycle,
+// to Sent: under the old flow the PO went to the supplier as soon as it was created.
+// Each moved order gets an audit entry so the jump is visible in its history.
+func migrateLegacyOrderStatuses(db *gorm.DB) error {
+	var ids []uint
+	if err := db.Model(&domain.SupplyOrder{}).Where("status = ?", domain.OrderStatusPending).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, id := range ids {
+			if err := tx.Model(&domain.SupplyOrder{}).Where("id = ?", id).Update("status", domain.OrderStatusSent).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&domain.SupplyOrderStatusChange{
+				SupplyOrderID: id,
+				FromStatus:    domain.OrderStatusPending,
+				ToStatus:      domain.OrderStatusSent,
+				Actor:         "system",
+				Note:          "Migrated from the legacy Pending status",
+				ChangedAt:     now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		log.Printf("Moved %d legacy Pending supply orders to Sent", len(ids))
+		return nil
+	})
+}
+
+// migrateIndentQuantities fills the approved and dispatched quantities of indents processed before
+// they were tracked, from the batches listed in their dispatch details.
+func migrateIndentQuantities(db *gorm.DB) error {
+	var indents []domain.Indent
+	err := db.Where("approved_quantity = 0 AND status IN ?", []string{
+		domain.IndentStatusProcessing, domain.IndentStatusDispatched, domain.IndentStatusFulfilled,
+	}).Find(&indents).Error
+	if err != nil || len(indents) == 0 {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrated := 0
+		for _, indent := range indents {
+			var details []struct {
+				Quantity int `json:"quantity"`
+			}
+			if json.Unmarshal([]byte(indent.DispatchDetails), &details) != nil {
+				continue
+			}
+			total := 0
+			for _, d := range details {
+				total += d.Quantity
+			}
+			if total == 0 {
+				continue
+			}
+
+			updates := map[string]interface{}{"approved_quantity": total}
+			if indent.Status != domain.IndentStatusProcessing {
+				updates["dispatched_quantity"] = total
+			}
+			if err := tx.Model(&domain.Indent{}).Where("id = ?", indent.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+			migrated++
+		}
+		if migrated > 0 {
+			log.Printf("Filled approved and dispatched quantities for %d indents", migrated)
+		}
+		return nil
+	})
+}
+
+// migrateIndentLines gives every indent from before multi-item indents a single line holding
+// its item, quantities, status and dispatch details, and moves its reservations onto that line.
+func migrateIndentLines(db *gorm.DB) error {
+	var indents []domain.Indent
+	err := db.Unscoped().Where("id NOT IN (?)", db.Model(&domain.IndentLine{}).Select("indent_id")).Find(&indents).Error
+	if err != nil || len(indents) == 0 {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, indent := range indents {
+			line := domain.IndentLine{
+				IndentID:           indent.ID,
+				ItemName:           indent.ItemName,
+				Quantity:           indent.Quantity,
+				ApprovedQuantity:   indent.ApprovedQuantity,
+				DispatchedQuantity: indent.DispatchedQuantity,
+				Status:             indent.Status,
+				DispatchDetails:    indent.DispatchDetails,
+			}
+			if err := tx.Create(&line).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.IndentReservation{}).
+				Where("indent_id = ? AND (indent_line_id IS NULL OR indent_line_id = 0)", indent.ID).
+				Update("indent_line_id", line.ID).Error; err != nil {
+				return err
+			}
+		}
+		log.Printf("Moved %d indents onto indent lines", len(indents))
+		return nil
+	})
+}
+
+// resolveLegacyItem finds the item an old blob entry meant. The item_id is trusted only when
+// its name agrees, since manually keyed rows carry a client timestamp there.
+func resolveLegacyItem(db *gorm.DB, li domain.LegacyOrderItem) *domain.Item {
+	name := strings.TrimSpace(li.ItemName)
+	var item domain.Item
+	if li.ItemID > 0 && li.ItemID < 1<<31 {
+		if db.Limit(1).Find(&item, uint(li.ItemID)).RowsAffected == 1 && (name == "" || strings.EqualFold(item.Name, name)) {
+			return &item
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	// Find rather than First, so an unknown name is not logged as an error
+	if db.Where("LOWER(name) = LOWER(?)", name).Limit(1).Find(&item).RowsAffected == 1 {
+		return &item
+	}
+	return nil
+}
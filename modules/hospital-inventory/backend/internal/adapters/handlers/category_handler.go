@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	service ports.CategoryService
+}
+
+func NewCategoryHandler(service ports.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+type categoryRequest struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	MinShelfLifeDays int    `json:"min_shelf_life_days"`
+}
+
+// ListCategories handles GET /api/categories
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.service.ListCategories(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// GetCategory handles GET /api/categories/:id
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	category, err := h.service.GetCategory(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// CreateCategory handles POST /api/categories
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category := &domain.Category{Name: req.Name, Description: req.Description, MinShelfLifeDays: req.MinShelfLifeDays}
+	if err := h.service.CreateCategory(c.Request.Context(), category); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory handles PUT /api/categories/:id
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category := &domain.Category{
+		BaseModel:        domain.BaseModel{ID: uint(id)},
+		Name:             req.Name,
+		Description:      req.Description,
+		MinShelfLifeDays: req.MinShelfLifeDays,
+	}
+	if err := h.service.UpdateCategory(c.Request.Context(), category); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory handles DELETE /api/categories/:id
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteCategory(c.Request.Context(), uint(id)); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted"})
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+	"hospital-inventory/internal/core/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeError maps domain errors to status codes, anything unrecognised is a 500.
+func writeError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrUnprocessable):
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
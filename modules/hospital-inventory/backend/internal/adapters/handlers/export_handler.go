@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"hospital-inventory/internal/adapters/spreadsheet"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var stockColumns = []spreadsheet.Column{
+	{Key: "item_id", Title: "Item ID"},
+	{Key: "item_name", Title: "Item"},
+	{Key: "category", Title: "Category"},
+	{Key: "unit", Title: "Unit"},
+	{Key: "batch_number", Title: "Batch Number"},
+	{Key: "expiry_date", Title: "Expiry Date"},
+	{Key: "quantity", Title: "Quantity"},
+	{Key: "mrp", Title: "MRP"},
+	{Key: "value", Title: "Value"},
+	{Key: "location", Title: "Location"},
+	{Key: "supplier", Title: "Supplier"},
+}
+
+var transactionColumns = []spreadsheet.Column{
+	{Key: "id", Title: "ID"},
+	{Key: "timestamp", Title: "Timestamp"},
+	{Key: "item_id", Title: "Item ID"},
+	{Key: "item_name", Title: "Item"},
+	{Key: "batch_number", Title: "Batch Number"},
+	{Key: "quantity_change", Title: "Quantity Change"},
+	{Key: "reason", Title: "Reason"},
+	{Key: "reference_id", Title: "Reference"},
+	{Key: "performed_by", Title: "Performed By"},
+	{Key: "notes", Title: "Notes"},
+}
+
+type ExportHandler struct {
+	service ports.ExportService
+}
+
+func NewExportHandler(service ports.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportItems handles GET /api/export/items
+// Streams batch-level stock. from/to filter on expiry date, category_id on the item's category.
+func (h *ExportHandler) ExportItems(c *gin.Context) {
+	format, filter, ok := parseExportQuery(c)
+	if !ok {
+		return
+	}
+
+	writer, ok := startExport(c, format, "stock", "Stock", stockColumns)
+	if !ok {
+		return
+	}
+	err := h.service.ExportStock(c.Request.Context(), filter, func(row domain.StockRow) error {
+		return writer.WriteRow(row.ItemID, row.ItemName, row.Category, row.Unit, row.BatchNumber,
+			row.ExpiryDate, row.Quantity, row.MRP, row.Value, row.Location, row.Supplier)
+	})
+	finishExport(writer, err)
+}
+
+// ExportAuditLogs handles GET /api/export/audit-logs
+// Streams the transaction ledger. from/to filter on the transaction timestamp.
+func (h *ExportHandler) ExportAuditLogs(c *gin.Context) {
+	format, filter, ok := parseExportQuery(c)
+	if !ok {
+		return
+	}
+
+	writer, ok := startExport(c, format, "audit-logs", "Audit Log", transactionColumns)
+	if !ok {
+		return
+	}
+	err := h.service.ExportTransactions(c.Request.Context(), filter, func(tx domain.InventoryTransaction) error {
+		batchNumber := ""
+		if tx.Batch != nil {
+			batchNumber = tx.Batch.BatchNumber
+		}
+		return writer.WriteRow(int(tx.ID), tx.Timestamp, int(tx.ItemID), tx.Item.Name, batchNumber,
+			tx.QuantityChange, tx.Reason, tx.ReferenceID, tx.PerformedBy, tx.Notes)
+	})
+	finishExport(writer, err)
+}
+
+// parseExportQuery reads format, from, to (YYYY-MM-DD, both inclusive) and category_id.
+func parseExportQuery(c *gin.Context) (string, domain.ExportFilter, bool) {
+	var filter domain.ExportFilter
+	format := strings.ToLower(c.DefaultQuery("format", spreadsheet.FormatCSV))
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX && format != spreadsheet.FormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": spreadsheet.ErrUnknownFormat.Error()})
+		return "", filter, false
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format, use YYYY-MM-DD"})
+			return "", filter, false
+		}
+		filter.From = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format, use YYYY-MM-DD"})
+			return "", filter, false
+		}
+		filter.To = t.AddDate(0, 0, 1)
+	}
+	if categoryStr := c.Query("category_id"); categoryStr != "" {
+		id, err := strconv.ParseUint(categoryStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return "", filter, false
+		}
+		categoryID := uint(id)
+		filter.CategoryID = &categoryID
+	}
+	return format, filter, true
+}
+
+// startExport sets download headers and writes the file header. Once it returns, the 200 status is committed.
+func startExport(c *gin.Context, format, filename, sheetName string, columns []spreadsheet.Column) (spreadsheet.Writer, bool) {
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, filename, time.Now().Format("20060102"), format))
+	c.Status(http.StatusOK)
+
+	writer, err := spreadsheet.NewWriter(c.Writer, format, sheetName, columns)
+	if err != nil {
+		log.Printf("export could not start: %v", err)
+		return nil, false
+	}
+	return writer, true
+}
+
+// finishExport closes the file. Errors after streaming has started can only be logged,
+// the client sees a truncated download.
+func finishExport(writer spreadsheet.Writer, err error) {
+	if err != nil {
+		log.Printf("export aborted: %v", err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("export failed to finish: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GoodsReceiptHandler struct {
+	service ports.GoodsReceiptService
+}
+
+func NewGoodsReceiptHandler(service ports.GoodsReceiptService) *GoodsReceiptHandler {
+	return &GoodsReceiptHandler{service: service}
+}
+
+type receiptLineRequest struct {
+	ItemID        uint     `json:"item_id"`
+	BatchNumber   string   `json:"batch_number"`
+	ExpiryDate    string   `json:"expiry_date"` // YYYY-MM-DD
+	Quantity      int      `json:"quantity"`    // Quantity received in this delivery
+	PurchasePrice float64  `json:"purchase_price"`
+	MRP           *float64 `json:"mrp"`
+	Location      string   `json:"location"`
+}
+
+type receiptRequest struct {
+	ReceivedBy string               `json:"received_by"`
+	Notes      string               `json:"notes"`
+	Lines      []receiptLineRequest `json:"lines"`
+}
+
+// ReceiveOrder handles POST /api/orders/:id/receipts
+func (h *GoodsReceiptHandler) ReceiveOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req receiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	receipt := &domain.GoodsReceipt{
+		ReceivedBy: req.ReceivedBy,
+		Notes:      req.Notes,
+	}
+	if receipt.ReceivedBy == "" {
+		// TODO: Get real User ID
+		receipt.ReceivedBy = "system"
+	}
+	for i, l := range req.Lines {
+		expiry, err := time.Parse("2006-01-02", l.ExpiryDate)
+		if err != nil && l.ExpiryDate != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Line %d: invalid expiry date format, use YYYY-MM-DD", i+1)})
+			return
+		}
+		receipt.Lines = append(receipt.Lines, domain.GoodsReceiptLine{
+			ItemID:        l.ItemID,
+			BatchNumber:   l.BatchNumber,
+			ExpiryDate:    expiry,
+			Quantity:      l.Quantity,
+			PurchasePrice: l.PurchasePrice,
+			MRP:           l.MRP,
+			Location:      l.Location,
+		})
+	}
+
+	if err := h.service.ReceiveOrder(c.Request.Context(), uint(id), receipt); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, receipt)
+}
+
+// ListReceipts handles GET /api/orders/:id/receipts
+func (h *GoodsReceiptHandler) ListReceipts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	receipts, err := h.service.ListReceipts(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, receipts)
+}
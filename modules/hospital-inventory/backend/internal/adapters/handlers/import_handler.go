@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"hospital-inventory/internal/adapters/spreadsheet"
+	"hospital-inventory/internal/core/ports"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize caps uploaded spreadsheets at 10 MB
+const maxImportSize = 10 << 20
+
+type ImportHandler struct {
+	service ports.ImportService
+}
+
+func NewImportHandler(service ports.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// ImportItems handles POST /api/import/items
+// Expects a multipart "file" field holding a CSV or XLSX sheet. With ?dry_run=true the file is only validated.
+func (h *ImportHandler) ImportItems(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A CSV or XLSX file is required in the 'file' field"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := spreadsheet.ReadTable(header.Filename, data)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, spreadsheet.ErrUnsupportedFormat) {
+			status = http.StatusUnsupportedMediaType
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// TODO: Get real User ID
+	userID := "system"
+
+	report, err := h.service.ImportItems(c.Request.Context(), rows, dryRun, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	case report.Committed:
+		c.JSON(http.StatusCreated, report)
+	default:
+		c.JSON(http.StatusOK, report)
+	}
+}
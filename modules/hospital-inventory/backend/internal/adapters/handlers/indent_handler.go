@@ -24,8 +24,11 @@ func (h *IndentHandler) CreateIndent(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateIndent(c.Request.Context(), &indent); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// TODO: Get real User ID
+	userID := "system"
+
+	if err := h.service.CreateIndent(c.Request.Context(), &indent, userID); err != nil {
+		writeError(c, err)
 		return
 	}
 
@@ -51,12 +54,30 @@ func (h *IndentHandler) GetIndent(c *gin.Context) {
 
 	indent, err := h.service.GetIndent(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Indent not found"})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, indent)
 }
 
+// GetHistory handles GET /api/indents/:id/history
+func (h *IndentHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	history, err := h.service.StatusHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// ProcessIndent handles PUT /api/indents/:id/status
+// Illegal transitions get 409 and unknown statuses 422.
 func (h *IndentHandler) ProcessIndent(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -66,17 +87,107 @@ func (h *IndentHandler) ProcessIndent(c *gin.Context) {
 	}
 
 	var req struct {
-		Status string `json:"status"`
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.service.ProcessIndent(c.Request.Context(), uint(id), req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// TODO: Get real User ID
+	userID := "system"
+
+	indent, err := h.service.ProcessIndent(c.Request.Context(), uint(id), req.Status, userID, req.Note)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Indent status updated"})
+	c.JSON(http.StatusOK, indent)
+}
+
+// ProcessIndentLine handles PUT /api/indents/:id/lines/:lineId/status
+// Illegal transitions get 409 and unknown statuses 422.
+func (h *IndentHandler) ProcessIndentLine(c *gin.Context) {
+	id, lineID, ok := indentLineIDs(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// TODO: Get real User ID
+	userID := "system"
+
+	indent, err := h.service.ProcessIndentLine(c.Request.Context(), id, lineID, req.Status, userID, req.Note)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, indent)
+}
+
+// GetPickList handles GET /api/indents/:id/lines/:lineId/picks
+func (h *IndentHandler) GetPickList(c *gin.Context) {
+	id, lineID, ok := indentLineIDs(c)
+	if !ok {
+		return
+	}
+
+	picks, err := h.service.PickList(c.Request.Context(), id, lineID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, picks)
+}
+
+// UpdatePickList handles PUT /api/indents/:id/lines/:lineId/picks
+// Batches without enough free stock get 409.
+func (h *IndentHandler) UpdatePickList(c *gin.Context) {
+	id, lineID, ok := indentLineIDs(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Picks []domain.IndentPick `json:"picks" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// TODO: Get real User ID
+	userID := "system"
+
+	indent, err := h.service.UpdatePickList(c.Request.Context(), id, lineID, req.Picks, userID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, indent)
+}
+
+// indentLineIDs reads the indent and line ids from the path, answering 400 when either is bad.
+func indentLineIDs(c *gin.Context) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, 0, false
+	}
+	lineID, err := strconv.ParseUint(c.Param("lineId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Line ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(lineID), true
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/ports"
@@ -45,6 +46,7 @@ func (h *InventoryHandler) GetKnowledgeBase(c *gin.Context) {
 // @Summary Create a new item
 type createItemRequest struct {
 	Name        string   `json:"name"`
+	CategoryID  *uint    `json:"category_id"`
 	Description string   `json:"description"`
 	Threshold   int      `json:"threshold"`
 	Unit        string   `json:"unit"`
@@ -53,6 +55,9 @@ type createItemRequest struct {
 	ExpiryDate  string   `json:"expiry_date"` // YYYY-MM-DD
 	Location    string   `json:"location"`
 	MRP         *float64 `json:"mrp"`
+	SupplierID  *uint    `json:"supplier_id"`
+	// Days of shelf life a batch needs to be dispatched, omit to use the category's
+	MinShelfLifeDays *int `json:"min_shelf_life_days"`
 }
 
 func (h *InventoryHandler) CreateItem(c *gin.Context) {
@@ -63,10 +68,12 @@ func (h *InventoryHandler) CreateItem(c *gin.Context) {
 	}
 
 	item := &domain.Item{
-		Name:        req.Name,
-		Description: req.Description,
-		Threshold:   req.Threshold,
-		Unit:        req.Unit,
+		Name:             req.Name,
+		CategoryID:       req.CategoryID,
+		Description:      req.Description,
+		Threshold:        req.Threshold,
+		Unit:             req.Unit,
+		MinShelfLifeDays: req.MinShelfLifeDays,
 	}
 
 	var batch *domain.Batch
@@ -86,10 +93,15 @@ func (h *InventoryHandler) CreateItem(c *gin.Context) {
 			ExpiryDate:  expiry,
 			Location:    req.Location,
 			MRP:         req.MRP,
+			SupplierID:  req.SupplierID,
 		}
 	}
 
 	if err := h.inventoryService.CreateItem(c.Request.Context(), item, batch); err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			writeError(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create item"})
 		return
 	}
@@ -106,6 +118,7 @@ type addBatchRequest struct {
 	ExpiryDate  string   `json:"expiry_date"` // YYYY-MM-DD
 	Location    string   `json:"location"`
 	MRP         *float64 `json:"mrp"`
+	SupplierID  *uint    `json:"supplier_id"`
 }
 
 // AddBatch godoc
@@ -130,18 +143,31 @@ func (h *InventoryHandler) AddBatch(c *gin.Context) {
 		ExpiryDate:  expiry,
 		Location:    req.Location,
 		MRP:         req.MRP,
+		SupplierID:  req.SupplierID,
 	}
 
 	// TODO: Get real User ID from Context (Auth Middleware)
 	userID := "system"
 
 	if err := h.inventoryService.AddBatch(c.Request.Context(), batch, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, batch)
 }
 
+// UpdateBatch godoc
+// @Summary Update a batch
+type updateBatchRequest struct {
+	BatchNumber   string   `json:"batch_number"`
+	Quantity      int      `json:"quantity"`
+	ExpiryDate    string   `json:"expiry_date"` // YYYY-MM-DD
+	Location      string   `json:"location"`
+	MRP           *float64 `json:"mrp"`
+	SupplierID    *uint    `json:"supplier_id"`    // Omit to keep the current supplier, 0 clears it
+	PurchasePrice *float64 `json:"purchase_price"` // Omit to keep the current purchase price
+}
+
 // UpdateBatch godoc
 // @Summary Update a batch
 func (h *InventoryHandler) UpdateBatch(c *gin.Context) {
@@ -152,7 +178,7 @@ func (h *InventoryHandler) UpdateBatch(c *gin.Context) {
 		return
 	}
 
-	var req addBatchRequest // Reuse request struct as fields are same
+	var req updateBatchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -164,21 +190,22 @@ func (h *InventoryHandler) UpdateBatch(c *gin.Context) {
 		return
 	}
 
-	batch := &domain.Batch{
-		BaseModel:   domain.BaseModel{ID: uint(id)},
-		ItemID:      req.ItemID,
-		BatchNumber: req.BatchNumber,
-		Quantity:    req.Quantity,
-		ExpiryDate:  expiry,
-		Location:    req.Location,
-		MRP:         req.MRP,
+	update := domain.BatchUpdate{
+		BatchNumber:   req.BatchNumber,
+		Quantity:      req.Quantity,
+		ExpiryDate:    expiry,
+		Location:      req.Location,
+		MRP:           req.MRP,
+		SupplierID:    req.SupplierID,
+		PurchasePrice: req.PurchasePrice,
 	}
 
 	// TODO: Get real User ID
 	userID := "system"
 
-	if err := h.inventoryService.UpdateBatch(c.Request.Context(), batch, "Manual Update", userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	batch, err := h.inventoryService.UpdateBatch(c.Request.Context(), uint(id), update, "Manual Update", userID)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, batch)
@@ -198,7 +225,7 @@ func (h *InventoryHandler) DeleteBatch(c *gin.Context) {
 	userID := "system"
 
 	if err := h.inventoryService.DeleteBatch(c.Request.Context(), uint(id), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Batch deleted"})
@@ -225,9 +252,12 @@ func (h *InventoryHandler) GetItem(c *gin.Context) {
 // @Summary Update an item
 type updateItemRequest struct {
 	Name        string `json:"name"`
+	CategoryID  *uint  `json:"category_id"` // Omit to keep the current category, 0 clears it
 	Description string `json:"description"`
 	Threshold   int    `json:"threshold"`
 	Unit        string `json:"unit"`
+	// Omit to keep the current minimum shelf life, a negative value falls back to the category's
+	MinShelfLifeDays *int `json:"min_shelf_life_days"`
 }
 
 func (h *InventoryHandler) UpdateItem(c *gin.Context) {
@@ -259,10 +289,22 @@ func (h *InventoryHandler) UpdateItem(c *gin.Context) {
 	item.Description = req.Description
 	item.Threshold = req.Threshold
 	item.Unit = req.Unit
+	if req.CategoryID != nil {
+		item.CategoryID = req.CategoryID
+		if *req.CategoryID == 0 {
+			item.CategoryID = nil
+		}
+	}
+	if req.MinShelfLifeDays != nil {
+		item.MinShelfLifeDays = req.MinShelfLifeDays
+		if *req.MinShelfLifeDays < 0 {
+			item.MinShelfLifeDays = nil
+		}
+	}
 
 	if err := h.inventoryService.UpdateItem(c.Request.Context(), item); err != nil {
 		fmt.Printf("Error updating item %d: %v\n", id, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, item)
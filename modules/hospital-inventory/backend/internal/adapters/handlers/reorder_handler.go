@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReorderHandler struct {
+	service ports.ReorderService
+}
+
+func NewReorderHandler(service ports.ReorderService) *ReorderHandler {
+	return &ReorderHandler{service: service}
+}
+
+// GetSuggestions handles GET /api/orders/suggestions
+// Optional days (consumption window), safety_days and cover_days tune the calculation.
+func (h *ReorderHandler) GetSuggestions(c *gin.Context) {
+	params := domain.ReorderParams{
+		WindowDays: domain.DefaultReorderWindowDays,
+		SafetyDays: domain.DefaultSafetyDays,
+		CoverDays:  domain.DefaultCoverDays,
+	}
+	for _, q := range []struct {
+		name string
+		min  int
+		dest *int
+	}{
+		{"days", 1, &params.WindowDays},
+		{"safety_days", 0, &params.SafetyDays},
+		{"cover_days", 1, &params.CoverDays},
+	} {
+		v := c.Query(q.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < q.min || n > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + q.name + ", expected a whole number from " + strconv.Itoa(q.min) + " to 365"})
+			return
+		}
+		*q.dest = n
+	}
+
+	report, err := h.service.SuggestReorders(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute reorder suggestions"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SupplierHandler struct {
+	service ports.SupplierService
+}
+
+func NewSupplierHandler(service ports.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: service}
+}
+
+type supplierRequest struct {
+	Name          string `json:"name"`
+	ContactPerson string `json:"contact_person"`
+	Phone         string `json:"phone"`
+	Email         string `json:"email"`
+	Address       string `json:"address"`
+	GSTIN         string `json:"gstin"`
+	LeadTimeDays  int    `json:"lead_time_days"`
+	PaymentTerms  string `json:"payment_terms"`
+}
+
+func (req supplierRequest) toDomain(id uint) *domain.Supplier {
+	return &domain.Supplier{
+		BaseModel:     domain.BaseModel{ID: id},
+		Name:          req.Name,
+		ContactPerson: req.ContactPerson,
+		Phone:         req.Phone,
+		Email:         req.Email,
+		Address:       req.Address,
+		GSTIN:         req.GSTIN,
+		LeadTimeDays:  req.LeadTimeDays,
+		PaymentTerms:  req.PaymentTerms,
+	}
+}
+
+// ListSuppliers handles GET /api/suppliers
+func (h *SupplierHandler) ListSuppliers(c *gin.Context) {
+	suppliers, err := h.service.ListSuppliers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, suppliers)
+}
+
+// GetSupplier handles GET /api/suppliers/:id
+func (h *SupplierHandler) GetSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	supplier, err := h.service.GetSupplier(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, supplier)
+}
+
+// CreateSupplier handles POST /api/suppliers
+func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
+	var req supplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier := req.toDomain(0)
+	if err := h.service.CreateSupplier(c.Request.Context(), supplier); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, supplier)
+}
+
+// UpdateSupplier handles PUT /api/suppliers/:id
+func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req supplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier := req.toDomain(uint(id))
+	if err := h.service.UpdateSupplier(c.Request.Context(), supplier); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, supplier)
+}
+
+// DeleteSupplier handles DELETE /api/suppliers/:id
+func (h *SupplierHandler) DeleteSupplier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.service.DeleteSupplier(c.Request.Context(), uint(id)); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier deleted"})
+}
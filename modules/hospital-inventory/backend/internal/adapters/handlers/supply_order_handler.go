@@ -1,28 +1,39 @@
 package handlers
 
 import (
-	"hospital-inventory/internal/adapters/repositories" // Assuming we use concrete repo for now as per other handlers
+	"bytes"
+	"hospital-inventory/internal/adapters/pdf"
 	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type SupplyOrderHandler struct {
-	Repo *repositories.SupplyOrderRepository
+	Service ports.SupplyOrderService
 }
 
-func NewSupplyOrderHandler(repo *repositories.SupplyOrderRepository) *SupplyOrderHandler {
-	return &SupplyOrderHandler{Repo: repo}
+func NewSupplyOrderHandler(service ports.SupplyOrderService) *SupplyOrderHandler {
+	return &SupplyOrderHandler{Service: service}
 }
 
-// CreateOrder handles POST /api/orders
+type orderLineRequest struct {
+	ItemID   uint    `json:"item_id"`
+	ItemName string  `json:"item_name"` // Used when item_id is not given
+	Quantity int     `json:"quantity"`
+	UnitCost float64 `json:"unit_cost"`
+	TaxRate  float64 `json:"tax_rate"` // GST percent
+}
+
+// CreateOrder handles POST /api/orders. Totals are computed from the lines; any total sent by the client is ignored.
 func (h *SupplyOrderHandler) CreateOrder(c *gin.Context) {
 	var req struct {
-		SupplierName string  `json:"supplier_name"`
-		Items        string  `json:"items"` // JSON string
-		TotalCost    float64 `json:"total_cost"`
+		SupplierID   *uint              `json:"supplier_id"`
+		SupplierName string             `json:"supplier_name"` // Accepted when supplier_id is not given
+		Lines        []orderLineRequest `json:"lines"`
+		Items        string             `json:"items"` // Deprecated JSON string, read only when lines is empty
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -31,15 +42,38 @@ func (h *SupplyOrderHandler) CreateOrder(c *gin.Context) {
 	}
 
 	order := domain.SupplyOrder{
+		SupplierID:   req.SupplierID,
 		SupplierName: req.SupplierName,
-		Status:       "Pending",
-		OrderDate:    time.Now(),
-		Items:        req.Items,
-		TotalCost:    req.TotalCost,
 	}
+	for _, l := range req.Lines {
+		order.Lines = append(order.Lines, domain.SupplyOrderLine{
+			ItemID:   l.ItemID,
+			ItemName: l.ItemName,
+			Quantity: l.Quantity,
+			UnitCost: l.UnitCost,
+			TaxRate:  l.TaxRate,
+		})
+	}
+	if len(req.Lines) == 0 && req.Items != "" {
+		legacy, err := domain.ParseLegacyOrderItems(req.Items)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "items is not valid JSON: " + err.Error()})
+			return
+		}
+		for _, l := range legacy {
+			line := domain.SupplyOrderLine{ItemName: l.ItemName, Quantity: l.Quantity, UnitCost: l.UnitCost}
+			if l.ItemID > 0 && l.ItemID < 1<<31 {
+				line.ItemID = uint(l.ItemID)
+			}
+			order.Lines = append(order.Lines, line)
+		}
+	}
+
+	// TODO: Get real User ID
+	userID := "system"
 
-	if err := h.Repo.CreateOrder(&order); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order"})
+	if err := h.Service.CreateOrder(c.Request.Context(), &order, userID); err != nil {
+		writeError(c, err)
 		return
 	}
 
@@ -48,7 +82,7 @@ func (h *SupplyOrderHandler) CreateOrder(c *gin.Context) {
 
 // ListOrders handles GET /api/orders
 func (h *SupplyOrderHandler) ListOrders(c *gin.Context) {
-	orders, err := h.Repo.ListOrders()
+	orders, err := h.Service.ListOrders(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
 		return
@@ -56,22 +90,93 @@ func (h *SupplyOrderHandler) ListOrders(c *gin.Context) {
 	c.JSON(http.StatusOK, orders)
 }
 
+// GetOrder handles GET /api/orders/:id
+func (h *SupplyOrderHandler) GetOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	order, err := h.Service.GetOrder(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// DownloadPDF handles GET /api/orders/:id/pdf
+// Renders the purchase order; ?inline=true shows it in the browser instead of downloading.
+func (h *SupplyOrderHandler) DownloadPDF(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	order, err := h.Service.GetOrder(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	// Render fully before writing, so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	if err := pdf.RenderPurchaseOrder(&buf, order, pdf.DefaultLetterhead); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render purchase order"})
+		return
+	}
+
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", disposition+`; filename="`+order.PONumber()+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
 // UpdateStatus handles PUT /api/orders/:id/status
+// Moves the order along its lifecycle. Illegal moves, such as reopening a cancelled order, get 409.
 func (h *SupplyOrderHandler) UpdateStatus(c *gin.Context) {
-	id := c.Param("id")
-	var req struct {
-		Status string `json:"status"`
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
 	}
 
+	var req struct {
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.Repo.UpdateStatus(id, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+	// TODO: Get real User ID
+	userID := "system"
+
+	order, err := h.Service.ChangeStatus(c.Request.Context(), uint(id), req.Status, userID, req.Note)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// GetHistory handles GET /api/orders/:id/history
+func (h *SupplyOrderHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Status updated"})
+	history, err := h.Service.StatusHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
 }
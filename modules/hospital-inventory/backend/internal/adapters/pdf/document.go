@@ -0,0 +1,202 @@
+// Package pdf writes simple text-and-shapes PDF documents without outside dependencies.
+// Output is byte-for-byte stable for the same input: nothing time or random based is written,
+// and content streams are left uncompressed so documents can be diffed.
+package pdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page sizes in millimetres.
+const (
+	A4Width  = 210.0
+	A4Height = 297.0
+)
+
+const ptPerMM = 72 / 25.4
+
+// Font is one of the PDF standard fonts, which every reader provides.
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = [...]string{Helvetica: "Helvetica", HelveticaBold: "Helvetica-Bold"}
+
+// Color is an RGB colour with 0-255 components.
+type Color struct{ R, G, B uint8 }
+
+// Align positions text relative to its x coordinate.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Document collects pages and writes them out with WriteTo.
+// Coordinates are in millimetres from the top-left corner of the page.
+type Document struct {
+	Title  string
+	Author string
+	pages  []*Page
+}
+
+// Page is a single A4 portrait page.
+type Page struct {
+	content strings.Builder
+}
+
+func NewDocument(title, author string) *Document {
+	return &Document{Title: title, Author: author}
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Pages returns the pages added so far, in order.
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Text draws s with its baseline at y. Characters outside Windows-1252 are replaced.
+func (p *Page) Text(x, y float64, font Font, size float64, color Color, align Align, s string) {
+	encoded := encodeText(s)
+	switch align {
+	case AlignCenter:
+		x -= encodedWidth(font, size, encoded) / 2
+	case AlignRight:
+		x -= encodedWidth(font, size, encoded)
+	}
+	fmt.Fprintf(&p.content, "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		rgb(color), font+1, num(size), num(x*ptPerMM), num((A4Height-y)*ptPerMM), escape(encoded))
+}
+
+// Rect fills a rectangle whose top-left corner is at x, y.
+func (p *Page) Rect(x, y, w, h float64, fill Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		rgb(fill), num(x*ptPerMM), num((A4Height-y-h)*ptPerMM), num(w*ptPerMM), num(h*ptPerMM))
+}
+
+// Line strokes a straight line of the given width in millimetres.
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		rgb(color), num(width*ptPerMM),
+		num(x1*ptPerMM), num((A4Height-y1)*ptPerMM), num(x2*ptPerMM), num((A4Height-y2)*ptPerMM))
+}
+
+// WriteTo writes the complete PDF file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	var offsets []int64
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Fixed object numbers: 1 catalog, 2 page tree, 3-4 fonts, 5 info, then a page and its content per page
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	fmt.Fprint(out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Author (%s) >>", escape(encodeText(d.Title)), escape(encodeText(d.Author))))
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(A4Width*ptPerMM), num(A4Height*ptPerMM), firstPage+2*i+1))
+		content := p.content.String()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if out.err != nil {
+		return out.n, out.err
+	}
+	return out.n, out.w.Flush()
+}
+
+// countingWriter tracks the byte offset for the xref table and keeps the first write error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+func rgb(c Color) string {
+	return fmt.Sprintf("%s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255))
+}
+
+// num formats with at most two decimals and no trailing zeros, so output does not depend on float noise.
+func num(v float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// encodeText converts UTF-8 to Windows-1252 bytes, the encoding the fonts are declared with.
+func encodeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		case r < 0x20:
+			// Control characters have no glyph
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			b.WriteByte(byte(r))
+		case r == '₹':
+			b.WriteString("Rs.")
+		default:
+			if c, ok := winAnsiExtras[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+// winAnsiExtras are the Windows-1252 characters outside Latin-1 that show up in typed text.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '…': 0x85, '™': 0x99,
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
@@ -0,0 +1,55 @@
+package pdf
+
+// Advance widths of printable ASCII (32-126) in 1/1000 em, from the Adobe core font metrics.
+var charWidths = [...][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// TextWidth returns the width of s in millimetres when set in font at size points.
+func TextWidth(font Font, size float64, s string) float64 {
+	return encodedWidth(font, size, encodeText(s))
+}
+
+func encodedWidth(font Font, size float64, encoded string) float64 {
+	units := 0
+	for i := 0; i < len(encoded); i++ {
+		c := encoded[i]
+		if c >= 32 && c <= 126 {
+			units += charWidths[font][c-32]
+		} else {
+			// Accented letters are close to the average lowercase width
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000 / ptPerMM
+}
+
+// Truncate shortens s with an ellipsis so it fits in width millimetres.
+func Truncate(font Font, size, width float64, s string) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if candidate := string(runes) + "..."; TextWidth(font, size, candidate) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,317 @@
+package pdf
+
+import (
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Letterhead is the issuing organisation printed on documents.
+type Letterhead struct {
+	Name    string
+	Address string
+	Contact string
+	// Dates are printed as they fall here, India time when nil, never in the server's zone
+	TimeZone *time.Location
+}
+
+// indiaTime is Asia/Kolkata, which has no daylight saving, without needing the zone database.
+var indiaTime = time.FixedZone("IST", 5*3600+1800)
+
+var DefaultLetterhead = Letterhead{
+	Name:     "spamMED Hospital Inventory",
+	Address:  "123 Health Avenue, MedCity",
+	Contact:  "support@spammed.com",
+	TimeZone: indiaTime,
+}
+
+var (
+	brandColor = Color{41, 128, 185}
+	textColor  = Color{52, 73, 94}
+	mutedColor = Color{120, 134, 150}
+	ruleColor  = Color{200, 200, 200}
+	stripe     = Color{245, 247, 250}
+	panel      = Color{240, 240, 240}
+	white      = Color{255, 255, 255}
+)
+
+const (
+	marginLeft  = 14.0
+	marginRight = A4Width - 14
+	footerTop   = A4Height - 20
+	rowHeight   = 7.0
+)
+
+// poColumn is one column of the line table. Text columns are left aligned at X,
+// figures are right aligned at X.
+type poColumn struct {
+	title string
+	x     float64
+	width float64
+	align Align
+}
+
+var poColumns = []poColumn{
+	{"#", marginLeft + 2, 6, AlignLeft},
+	{"Item", marginLeft + 10, 74, AlignLeft},
+	{"Qty", 112, 16, AlignRight},
+	{"Unit Cost", 136, 22, AlignRight},
+	{"GST %", 151, 13, AlignRight},
+	{"Tax", 171, 18, AlignRight},
+	{"Amount", marginRight - 2, 23, AlignRight},
+}
+
+// RenderPurchaseOrder writes the order as a PDF purchase order: letterhead, supplier, line table,
+// tax summary, totals and terms. Only the order's own dates are printed, so the same order always
+// renders to the same bytes.
+func RenderPurchaseOrder(w io.Writer, order *domain.SupplyOrder, head Letterhead) error {
+	number := order.PONumber()
+	doc := NewDocument("Purchase Order "+number, head.Name)
+	r := &poRenderer{doc: doc, order: order, head: head, number: number}
+
+	r.page = doc.AddPage()
+	r.banner()
+	r.details()
+	r.y = 100
+	r.tableHeader()
+	for i, line := range order.Lines {
+		if r.y+rowHeight > footerTop-5 {
+			r.continuationPage()
+			r.tableHeader()
+		}
+		r.tableRow(i, line)
+	}
+
+	// Totals, terms and signature stay together on one page
+	if r.y+95 > footerTop {
+		r.continuationPage()
+	}
+	r.totals()
+	r.terms()
+	r.signature()
+
+	pages := doc.Pages()
+	for i, p := range pages {
+		footer(p, head, i+1, len(pages))
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+type poRenderer struct {
+	doc    *Document
+	page   *Page
+	order  *domain.SupplyOrder
+	head   Letterhead
+	number string
+	y      float64
+}
+
+func (r *poRenderer) banner() {
+	p := r.page
+	p.Rect(0, 0, A4Width, 40, brandColor)
+	p.Text(marginLeft, 25, HelveticaBold, 26, white, AlignLeft, "PURCHASE ORDER")
+	p.Text(marginRight, 18, HelveticaBold, 11, white, AlignRight, r.head.Name)
+	p.Text(marginRight, 24, Helvetica, 9, white, AlignRight, r.head.Address)
+	p.Text(marginRight, 29, Helvetica, 9, white, AlignRight, r.head.Contact)
+}
+
+func (r *poRenderer) details() {
+	p := r.page
+	const top = 55.0
+	const right = 110.0
+
+	p.Text(marginLeft, top, HelveticaBold, 10, textColor, AlignLeft, "ORDER DETAILS")
+	p.Line(marginLeft, top+2, 90, top+2, 0.3, ruleColor)
+	rows := [][2]string{
+		{"PO Number", r.number},
+		{"Order Date", r.date(r.order.OrderDate)},
+		{"Status", r.order.Status},
+	}
+	for i, row := range rows {
+		y := top + 9 + float64(i)*6
+		p.Text(marginLeft, y, HelveticaBold, 9, mutedColor, AlignLeft, row[0])
+		p.Text(marginLeft+25, y, Helvetica, 9, textColor, AlignLeft, row[1])
+	}
+
+	p.Text(right, top, HelveticaBold, 10, textColor, AlignLeft, "SUPPLIER")
+	p.Line(right, top+2, marginRight, top+2, 0.3, ruleColor)
+	y := top + 9
+	p.Text(right, y, HelveticaBold, 10, textColor, AlignLeft, Truncate(HelveticaBold, 10, marginRight-right, r.order.SupplierName))
+	if s := r.order.Supplier; s != nil {
+		for _, text := range []string{
+			labelled("Attn", s.ContactPerson),
+			s.Address,
+			joinNonEmpty(" | ", s.Phone, s.Email),
+			labelled("GSTIN", s.GSTIN),
+		} {
+			if text == "" {
+				continue
+			}
+			y += 5
+			p.Text(right, y, Helvetica, 9, textColor, AlignLeft, Truncate(Helvetica, 9, marginRight-right, text))
+		}
+	}
+}
+
+func (r *poRenderer) continuationPage() {
+	r.page = r.doc.AddPage()
+	r.page.Text(marginLeft, 15, HelveticaBold, 10, textColor, AlignLeft, "PURCHASE ORDER "+r.number)
+	r.page.Text(marginRight, 15, Helvetica, 9, mutedColor, AlignRight, "continued")
+	r.y = 22
+}
+
+func (r *poRenderer) tableHeader() {
+	r.page.Rect(marginLeft, r.y, marginRight-marginLeft, 8, brandColor)
+	for _, col := range poColumns {
+		r.page.Text(col.x, r.y+5.5, HelveticaBold, 9, white, col.align, col.title)
+	}
+	r.y += 8
+}
+
+func (r *poRenderer) tableRow(i int, line domain.SupplyOrderLine) {
+	if i%2 == 1 {
+		r.page.Rect(marginLeft, r.y, marginRight-marginLeft, rowHeight, stripe)
+	}
+	values := []string{
+		strconv.Itoa(i + 1),
+		line.ItemName,
+		strconv.Itoa(line.Quantity),
+		formatMoney(line.UnitCost),
+		formatRate(line.TaxRate),
+		formatMoney(line.TaxAmount),
+		formatMoney(line.LineTotal),
+	}
+	for c, col := range poColumns {
+		font := Helvetica
+		if c == len(poColumns)-1 {
+			font = HelveticaBold
+		}
+		r.page.Text(col.x, r.y+4.8, font, 9, textColor, col.align, Truncate(font, 9, col.width, values[c]))
+	}
+	r.y += rowHeight
+	r.page.Line(marginLeft, r.y, marginRight, r.y, 0.1, ruleColor)
+}
+
+// totals prints GST grouped by rate on the left and the order totals on the right.
+func (r *poRenderer) totals() {
+	p := r.page
+	top := r.y + 8
+
+	byRate := make(map[float64]float64)
+	for _, l := range r.order.Lines {
+		byRate[l.TaxRate] += l.TaxAmount
+	}
+	rates := make([]float64, 0, len(byRate))
+	for rate := range byRate {
+		rates = append(rates, rate)
+	}
+	sort.Float64s(rates)
+
+	p.Text(marginLeft, top, HelveticaBold, 9, textColor, AlignLeft, "TAX SUMMARY")
+	y := top
+	for _, rate := range rates {
+		y += 5
+		p.Text(marginLeft, y, Helvetica, 9, textColor, AlignLeft, "GST @ "+formatRate(rate)+"%")
+		p.Text(marginLeft+55, y, Helvetica, 9, textColor, AlignRight, formatMoney(domain.RoundMoney(byRate[rate])))
+	}
+
+	const labelX = 132.0
+	p.Text(labelX, top, Helvetica, 9, textColor, AlignLeft, "Subtotal")
+	p.Text(marginRight-2, top, Helvetica, 9, textColor, AlignRight, formatMoney(r.order.Subtotal))
+	p.Text(labelX, top+6, Helvetica, 9, textColor, AlignLeft, "GST")
+	p.Text(marginRight-2, top+6, Helvetica, 9, textColor, AlignRight, formatMoney(r.order.TaxTotal))
+	p.Rect(labelX-4, top+10, marginRight-labelX+4, 10, panel)
+	p.Text(labelX, top+16.5, HelveticaBold, 11, textColor, AlignLeft, "Grand Total")
+	p.Text(marginRight-2, top+16.5, HelveticaBold, 11, brandColor, AlignRight, formatMoney(r.order.TotalCost))
+
+	r.y = top + 20
+	if y > r.y {
+		r.y = y
+	}
+}
+
+func (r *poRenderer) terms() {
+	p := r.page
+	r.y += 12
+	p.Text(marginLeft, r.y, HelveticaBold, 9, textColor, AlignLeft, "TERMS & CONDITIONS")
+	p.Line(marginLeft, r.y+2, 90, r.y+2, 0.3, ruleColor)
+
+	payment := "As agreed with the supplier."
+	delivery := "Deliver at the earliest."
+	if s := r.order.Supplier; s != nil {
+		if s.PaymentTerms != "" {
+			payment = s.PaymentTerms + "."
+		}
+		if s.LeadTimeDays > 0 && !r.order.OrderDate.IsZero() {
+			delivery = fmt.Sprintf("Deliver within %d days, by %s.", s.LeadTimeDays,
+				r.date(r.order.OrderDate.AddDate(0, 0, s.LeadTimeDays)))
+		}
+	}
+	terms := []string{
+		"Payment: " + payment,
+		"Delivery: " + delivery,
+		"Every pack must show its batch number and expiry date. Short-dated stock may be refused.",
+		"Quote " + r.number + " on all invoices and delivery challans.",
+	}
+	for i, t := range terms {
+		r.y += 5.5
+		p.Text(marginLeft, r.y, Helvetica, 8.5, textColor, AlignLeft,
+			Truncate(Helvetica, 8.5, marginRight-marginLeft, fmt.Sprintf("%d. %s", i+1, t)))
+	}
+}
+
+func (r *poRenderer) signature() {
+	y := r.y + 25
+	r.page.Line(marginRight-66, y, marginRight, y, 0.3, textColor)
+	r.page.Text(marginRight, y+5, Helvetica, 8, textColor, AlignRight, "Authorized Signature, "+r.head.Name)
+}
+
+func footer(p *Page, head Letterhead, page, pages int) {
+	p.Rect(0, footerTop, A4Width, A4Height-footerTop, Color{245, 245, 245})
+	p.Text(A4Width/2, footerTop+10, Helvetica, 8, mutedColor, AlignCenter,
+		"Generated by "+head.Name+" | Contact: "+head.Contact)
+	p.Text(marginRight, footerTop+10, Helvetica, 8, mutedColor, AlignRight, fmt.Sprintf("Page %d of %d", page, pages))
+}
+
+func formatMoney(v float64) string {
+	return "Rs. " + strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// formatRate prints 12 as "12" and 2.5 as "2.5".
+func formatRate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// date prints t as the day it is in the letterhead's time zone, so a document reads the same on every server.
+func (r *poRenderer) date(t time.Time) string {
+	loc := r.head.TimeZone
+	if loc == nil {
+		loc = indiaTime
+	}
+	return t.In(loc).Format("02 Jan 2006")
+}
+
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + ": " + value
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	out := ""
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if out != "" {
+			out += sep
+		}
+		out += v
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package pdf
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testOrder is a fixed order. It is dated 02:00 on 1 March in India, still 28 February in UTC,
+// so a document dated in the wrong zone shows up.
+func testOrder(lines int, itemName func(i int) string) *domain.SupplyOrder {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	order := &domain.SupplyOrder{
+		BaseModel:    domain.BaseModel{ID: 42},
+		SupplierName: "MedSupply Distributors",
+		Supplier: &domain.Supplier{
+			Name:          "MedSupply Distributors",
+			ContactPerson: "R. Sharma",
+			Phone:         "+91 98765 43210",
+			Email:         "orders@medsupply.example",
+			Address:       "14 Industrial Estate, Pune",
+			GSTIN:         "27ABCDE1234F1Z5",
+			LeadTimeDays:  7,
+			PaymentTerms:  "Net 30",
+		},
+		Status:    domain.OrderStatusSent,
+		OrderDate: time.Date(2026, 3, 1, 2, 0, 0, 0, ist),
+	}
+	rates := []float64{5, 12, 18, 2.5}
+	for i := 0; i < lines; i++ {
+		order.Lines = append(order.Lines, domain.SupplyOrderLine{
+			ItemName: itemName(i),
+			Quantity: 10 * (i + 1),
+			UnitCost: 1.25 + float64(i),
+			TaxRate:  rates[i%len(rates)],
+		})
+	}
+	order.ComputeTotals()
+	return order
+}
+
+func render(t *testing.T, order *domain.SupplyOrder) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := RenderPurchaseOrder(&buf, order, DefaultLetterhead); err != nil {
+		t.Fatalf("RenderPurchaseOrder: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestRenderPurchaseOrderGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *domain.SupplyOrder
+		pages int
+		want  []string // fragments of the page content, as encoded in the file
+	}{
+		{
+			name:  "single_page",
+			order: testOrder(3, func(i int) string { return fmt.Sprintf("Paracetamol 500mg strip %d", i+1) }),
+			pages: 1,
+			want:  []string{"(PO-000042)", "(01 Mar 2026)", "(Page 1 of 1)", "by 08 Mar 2026"},
+		},
+		{
+			name:  "multi_page",
+			order: testOrder(60, func(i int) string { return fmt.Sprintf("Item %02d", i+1) }),
+			pages: 3,
+			want:  []string{"(PURCHASE ORDER PO-000042)", "(continued)", "(Page 3 of 3)", "(Item 60)"},
+		},
+		{
+			name: "non_latin1",
+			order: testOrder(4, func(i int) string {
+				return []string{"Crème – 5% ₹", "Amoxicillin “250mg”", "पैरासिटामोल", "Ointment (10g) \\ tube"}[i]
+			}),
+			pages: 1,
+			want:  []string{"(Cr\xe8me \x96 5% Rs.)", "(Amoxicillin \x93250mg\x94)", "(???????????)", `(Ointment \(10g\) \\ tube)`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := render(t, tt.order)
+
+			if n := bytes.Count(got, []byte("/Type /Page /Parent")); n != tt.pages {
+				t.Errorf("rendered %d pages, want %d", n, tt.pages)
+			}
+			for _, w := range tt.want {
+				if !bytes.Contains(got, []byte(w)) {
+					t.Errorf("output is missing %q", w)
+				}
+			}
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s, run go test -update if the change is intended", golden)
+			}
+		})
+	}
+}
+
+func TestRenderPurchaseOrderIgnoresLocalTimeZone(t *testing.T) {
+	order := testOrder(2, func(i int) string { return "Gauze" })
+	saved := time.Local
+	defer func() { time.Local = saved }()
+
+	time.Local = time.UTC
+	inUTC := render(t, order)
+	time.Local = time.FixedZone("PST", -8*3600)
+	order.OrderDate = order.OrderDate.In(time.Local)
+	if got := render(t, order); !bytes.Equal(got, inUTC) {
+		t.Error("output changed with the local time zone")
+	}
+}
+
+func TestRenderPurchaseOrderLetterheadTimeZone(t *testing.T) {
+	tests := []struct {
+		name string
+		zone *time.Location
+		want string
+	}{
+		{"unset", nil, "(01 Mar 2026)"},
+		{"UTC", time.UTC, "(28 Feb 2026)"},
+		{"PST", time.FixedZone("PST", -8*3600), "(28 Feb 2026)"},
+	}
+	for _, tt := range tests {
+		head := DefaultLetterhead
+		head.TimeZone = tt.zone
+		var buf bytes.Buffer
+		if err := RenderPurchaseOrder(&buf, testOrder(1, func(i int) string { return "Gauze" }), head); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(buf.Bytes(), []byte(tt.want)) {
+			t.Errorf("%s time zone: output is missing %q", tt.name, tt.want)
+		}
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Dolo 650", "Dolo 650"},
+		{"Crème brûlée", "Cr\xe8me br\xfbl\xe9e"},
+		{"₹120", "Rs.120"},
+		{"a – b … c", "a \x96 b \x85 c"},
+		{"tab\there\nnew", "tab here new"},
+		{"bell\x07", "bell"},
+		{"日本", "??"},
+	}
+	for _, tt := range tests {
+		if got := encodeText(tt.in); got != tt.want {
+			t.Errorf("encodeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateFitsWidth(t *testing.T) {
+	long := strings.Repeat("Amoxicillin and Clavulanate Potassium ", 4)
+	got := Truncate(Helvetica, 9, 74, long)
+	if got == long {
+		t.Fatal("long text was not truncated")
+	}
+	if w := TextWidth(Helvetica, 9, got); w > 74 {
+		t.Errorf("truncated text is %.1fmm wide, want at most 74", w)
+	}
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type GormCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCategoryRepository(db *gorm.DB) ports.CategoryRepository {
+	return &GormCategoryRepository{db: db}
+}
+
+func (r *GormCategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *GormCategoryRepository) GetByID(ctx context.Context, id uint) (*domain.Category, error) {
+	var category domain.Category
+	err := r.db.WithContext(ctx).First(&category, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: category %d", domain.ErrNotFound, id)
+	}
+	return &category, err
+}
+
+func (r *GormCategoryRepository) GetByName(ctx context.Context, name string) (*domain.Category, error) {
+	var category domain.Category
+	err := r.db.WithContext(ctx).Where("lower(name) = lower(?)", name).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: category %q", domain.ErrNotFound, name)
+	}
+	return &category, err
+}
+
+func (r *GormCategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Omit("Items").Save(category).Error
+}
+
+// Delete removes the row outright. Names are unique, so a soft-deleted row would block recreating it.
+func (r *GormCategoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&domain.Category{}, id).Error
+}
+
+func (r *GormCategoryRepository) List(ctx context.Context) ([]domain.Category, error) {
+	var categories []domain.Category
+	err := r.db.WithContext(ctx).Order("name asc").Find(&categories).Error
+	return categories, err
+}
+
+func (r *GormCategoryRepository) CountItems(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Item{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
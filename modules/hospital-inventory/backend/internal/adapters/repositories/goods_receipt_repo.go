@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type GormGoodsReceiptRepository struct {
+	db *gorm.DB
+}
+
+func NewGormGoodsReceiptRepository(db *gorm.DB) ports.GoodsReceiptRepository {
+	return &GormGoodsReceiptRepository{db: db}
+}
+
+func (r *GormGoodsReceiptRepository) Create(ctx context.Context, receipt *domain.GoodsReceipt) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Create(receipt).Error; err != nil {
+		return err
+	}
+	// GRN numbers follow the row id so they stay sequential
+	receipt.ReceiptNumber = fmt.Sprintf("GRN-%06d", receipt.ID)
+	return db.Model(receipt).Update("receipt_number", receipt.ReceiptNumber).Error
+}
+
+func (r *GormGoodsReceiptRepository) ListByOrder(ctx context.Context, orderID uint) ([]domain.GoodsReceipt, error) {
+	var receipts []domain.GoodsReceipt
+	err := r.db.WithContext(ctx).Preload("Lines").Where("supply_order_id = ?", orderID).Order("id asc").Find(&receipts).Error
+	return receipts, err
+}
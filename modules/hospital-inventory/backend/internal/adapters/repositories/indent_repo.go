@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/ports"
 
@@ -24,18 +26,54 @@ func (r *GormIndentRepository) UpdateStatus(ctx context.Context, id uint, status
 	return r.db.WithContext(ctx).Model(&domain.Indent{}).Where("id = ?", id).Update("status", status).Error
 }
 
+// Update saves the indent's own fields, lines are saved with UpdateLine.
 func (r *GormIndentRepository) Update(ctx context.Context, indent *domain.Indent) error {
-	return r.db.WithContext(ctx).Save(indent).Error
+	return r.db.WithContext(ctx).Omit("Lines").Save(indent).Error
+}
+
+func (r *GormIndentRepository) UpdateLine(ctx context.Context, line *domain.IndentLine) error {
+	return r.db.WithContext(ctx).Save(line).Error
 }
 
 func (r *GormIndentRepository) List(ctx context.Context) ([]domain.Indent, error) {
 	var indents []domain.Indent
-	err := r.db.WithContext(ctx).Order("created_at desc").Find(&indents).Error
+	err := r.db.WithContext(ctx).Preload("Lines", orderedLines).Order("created_at desc").Find(&indents).Error
 	return indents, err
 }
 
 func (r *GormIndentRepository) GetByID(ctx context.Context, id uint) (*domain.Indent, error) {
 	var indent domain.Indent
-	err := r.db.WithContext(ctx).First(&indent, id).Error
+	err := r.db.WithContext(ctx).Preload("Lines", orderedLines).First(&indent, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: indent %d", domain.ErrNotFound, id)
+	}
 	return &indent, err
 }
+
+func (r *GormIndentRepository) AddStatusChange(ctx context.Context, change *domain.IndentStatusChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *GormIndentRepository) ListStatusChanges(ctx context.Context, indentID uint) ([]domain.IndentStatusChange, error) {
+	var changes []domain.IndentStatusChange
+	err := r.db.WithContext(ctx).Where("indent_id = ?", indentID).Order("changed_at, id").Find(&changes).Error
+	return changes, err
+}
+
+func (r *GormIndentRepository) AddReservation(ctx context.Context, reservation *domain.IndentReservation) error {
+	return r.db.WithContext(ctx).Omit("Batch").Create(reservation).Error
+}
+
+func (r *GormIndentRepository) ListReservations(ctx context.Context, lineID uint) ([]domain.IndentReservation, error) {
+	var reservations []domain.IndentReservation
+	err := r.db.WithContext(ctx).Preload("Batch", unscoped).Where("indent_line_id = ?", lineID).Order("id").Find(&reservations).Error
+	return reservations, err
+}
+
+func (r *GormIndentRepository) DeleteReservations(ctx context.Context, lineID uint) error {
+	return r.db.WithContext(ctx).Where("indent_line_id = ?", lineID).Delete(&domain.IndentReservation{}).Error
+}
+
+func orderedLines(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
@@ -2,12 +2,19 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/ports"
 
 	"gorm.io/gorm"
 )
 
+// unscoped preloads soft-deleted associations, so old batches still show who supplied them
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
 type GormItemRepository struct {
 	db *gorm.DB
 }
@@ -22,14 +29,14 @@ func (r *GormItemRepository) Create(ctx context.Context, item *domain.Item) erro
 
 func (r *GormItemRepository) GetByID(ctx context.Context, id uint) (*domain.Item, error) {
 	var item domain.Item
-	// Preload Batches and Category
-	err := r.db.WithContext(ctx).Preload("Batches").Preload("Category").First(&item, id).Error
+	// Preload Batches (with their supplier) and Category
+	err := r.db.WithContext(ctx).Preload("Batches").Preload("Batches.Supplier", unscoped).Preload("Category").First(&item, id).Error
 	return &item, err
 }
 
 func (r *GormItemRepository) GetByName(ctx context.Context, name string) (*domain.Item, error) {
 	var item domain.Item
-	err := r.db.WithContext(ctx).Preload("Batches").Preload("Category").Where("name = ?", name).First(&item).Error
+	err := r.db.WithContext(ctx).Preload("Batches").Preload("Batches.Supplier", unscoped).Preload("Category").Where("name = ?", name).First(&item).Error
 	return &item, err
 }
 
@@ -44,6 +51,7 @@ func (r *GormItemRepository) List(ctx context.Context) ([]domain.Item, error) {
 		Distinct("items.*").
 		Joins("INNER JOIN hospital_batches ON hospital_batches.item_id = items.id").
 		Preload("Batches").
+		Preload("Batches.Supplier", unscoped).
 		Preload("Category").
 		Find(&items).Error
 	return items, err
@@ -73,13 +81,17 @@ func (r *GormBatchRepository) Create(ctx context.Context, batch *domain.Batch) e
 	return r.db.WithContext(ctx).Create(batch).Error
 }
 
+// Update never writes ReservedQuantity, a stale copy of the batch must not undo a reservation.
 func (r *GormBatchRepository) Update(ctx context.Context, batch *domain.Batch) error {
-	return r.db.WithContext(ctx).Save(batch).Error
+	return r.db.WithContext(ctx).Omit("ReservedQuantity").Save(batch).Error
 }
 
 func (r *GormBatchRepository) GetByID(ctx context.Context, id uint) (*domain.Batch, error) {
 	var batch domain.Batch
 	err := r.db.WithContext(ctx).First(&batch, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: batch %d", domain.ErrNotFound, id)
+	}
 	return &batch, err
 }
 
@@ -92,3 +104,79 @@ func (r *GormBatchRepository) GetByItemID(ctx context.Context, itemID uint) ([]d
 	err := r.db.WithContext(ctx).Where("item_id = ?", itemID).Order("expiry_date asc").Find(&batches).Error
 	return batches, err
 }
+
+func (r *GormBatchRepository) StreamStock(ctx context.Context, filter domain.ExportFilter, fn func(domain.StockRow) error) error {
+	query := r.db.WithContext(ctx).
+		Table("hospital_batches").
+		Select(`items.id AS item_id, items.name AS item_name, coalesce(categories.name, '') AS category, items.unit,
+			hospital_batches.id AS batch_id, hospital_batches.batch_number, hospital_batches.expiry_date,
+			hospital_batches.quantity, hospital_batches.mrp, hospital_batches.location,
+			coalesce(suppliers.name, '') AS supplier`).
+		Joins("JOIN items ON items.id = hospital_batches.item_id AND items.deleted_at IS NULL").
+		Joins("LEFT JOIN categories ON categories.id = items.category_id").
+		Joins("LEFT JOIN suppliers ON suppliers.id = hospital_batches.supplier_id").
+		Where("hospital_batches.deleted_at IS NULL AND hospital_batches.quantity <> 0")
+
+	if !filter.From.IsZero() {
+		query = query.Where("hospital_batches.expiry_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("hospital_batches.expiry_date < ?", filter.To)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("items.category_id = ?", *filter.CategoryID)
+	}
+
+	rows, err := query.Order("items.name asc, hospital_batches.expiry_date asc").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row domain.StockRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *GormBatchRepository) Reserve(ctx context.Context, batchID uint, qty int) error {
+	// The guard in the WHERE clause makes the check and the update a single step on the row
+	res := r.db.WithContext(ctx).Model(&domain.Batch{}).
+		Where("id = ? AND quantity - reserved_quantity >= ?", batchID, qty).
+		Update("reserved_quantity", gorm.Expr("reserved_quantity + ?", qty))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: batch %d no longer has %d unreserved", domain.ErrConflict, batchID, qty)
+	}
+	return nil
+}
+
+func (r *GormBatchRepository) Release(ctx context.Context, batchID uint, qty int) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&domain.Batch{}).
+		Where("id = ?", batchID).
+		Update("reserved_quantity", gorm.Expr("MAX(reserved_quantity - ?, 0)", qty)).Error
+}
+
+func (r *GormBatchRepository) ConsumeReserved(ctx context.Context, batchID uint, qty int) error {
+	res := r.db.WithContext(ctx).Model(&domain.Batch{}).
+		Where("id = ? AND quantity >= ? AND reserved_quantity >= ?", batchID, qty, qty).
+		Updates(map[string]interface{}{
+			"quantity":          gorm.Expr("quantity - ?", qty),
+			"reserved_quantity": gorm.Expr("reserved_quantity - ?", qty),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: batch %d does not hold %d reserved units", domain.ErrConflict, batchID, qty)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type GormSupplierRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSupplierRepository(db *gorm.DB) ports.SupplierRepository {
+	return &GormSupplierRepository{db: db}
+}
+
+func (r *GormSupplierRepository) Create(ctx context.Context, supplier *domain.Supplier) error {
+	return r.db.WithContext(ctx).Create(supplier).Error
+}
+
+func (r *GormSupplierRepository) GetByID(ctx context.Context, id uint) (*domain.Supplier, error) {
+	var supplier domain.Supplier
+	err := r.db.WithContext(ctx).First(&supplier, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: supplier %d", domain.ErrNotFound, id)
+	}
+	return &supplier, err
+}
+
+func (r *GormSupplierRepository) GetByName(ctx context.Context, name string) (*domain.Supplier, error) {
+	var supplier domain.Supplier
+	err := r.db.WithContext(ctx).Where("lower(name) = lower(?)", name).First(&supplier).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: supplier %q", domain.ErrNotFound, name)
+	}
+	return &supplier, err
+}
+
+func (r *GormSupplierRepository) Update(ctx context.Context, supplier *domain.Supplier) error {
+	return r.db.WithContext(ctx).Save(supplier).Error
+}
+
+// Delete is a soft delete, batches and orders keep pointing at the supplier for traceability.
+func (r *GormSupplierRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Supplier{}, id).Error
+}
+
+func (r *GormSupplierRepository) List(ctx context.Context) ([]domain.Supplier, error) {
+	var suppliers []domain.Supplier
+	err := r.db.WithContext(ctx).Order("name asc").Find(&suppliers).Error
+	return suppliers, err
+}
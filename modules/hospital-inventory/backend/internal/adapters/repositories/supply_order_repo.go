@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"hospital-inventory/internal/core/domain"
 
 	"gorm.io/gorm"
@@ -14,16 +17,63 @@ func NewSupplyOrderRepository(db *gorm.DB) *SupplyOrderRepository {
 	return &SupplyOrderRepository{db: db}
 }
 
-func (r *SupplyOrderRepository) CreateOrder(order *domain.SupplyOrder) error {
-	return r.db.Create(order).Error
+// Create saves the order together with its lines.
+func (r *SupplyOrderRepository) Create(ctx context.Context, order *domain.SupplyOrder) error {
+	return r.db.WithContext(ctx).Omit("Supplier").Create(order).Error
 }
 
-func (r *SupplyOrderRepository) ListOrders() ([]domain.SupplyOrder, error) {
+func (r *SupplyOrderRepository) List(ctx context.Context) ([]domain.SupplyOrder, error) {
 	var orders []domain.SupplyOrder
-	result := r.db.Order("created_at desc").Find(&orders)
+	result := r.db.WithContext(ctx).Preload("Supplier", unscoped).Preload("Lines").Order("created_at desc").Find(&orders)
 	return orders, result.Error
 }
 
-func (r *SupplyOrderRepository) UpdateStatus(id string, status string) error {
-	return r.db.Model(&domain.SupplyOrder{}).Where("id = ?", id).Update("status", status).Error
+func (r *SupplyOrderRepository) GetByID(ctx context.Context, id uint) (*domain.SupplyOrder, error) {
+	var order domain.SupplyOrder
+	err := r.db.WithContext(ctx).Preload("Supplier", unscoped).Preload("Lines").First(&order, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: supply order %d", domain.ErrNotFound, id)
+	}
+	return &order, err
+}
+
+// Update saves the order header only, lines are updated with UpdateLine.
+func (r *SupplyOrderRepository) Update(ctx context.Context, order *domain.SupplyOrder) error {
+	return r.db.WithContext(ctx).Omit("Supplier", "Lines").Save(order).Error
+}
+
+func (r *SupplyOrderRepository) UpdateLine(ctx context.Context, line *domain.SupplyOrderLine) error {
+	return r.db.WithContext(ctx).Save(line).Error
+}
+
+func (r *SupplyOrderRepository) AddStatusChange(ctx context.Context, change *domain.SupplyOrderStatusChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *SupplyOrderRepository) ListStatusChanges(ctx context.Context, orderID uint) ([]domain.SupplyOrderStatusChange, error) {
+	var changes []domain.SupplyOrderStatusChange
+	err := r.db.WithContext(ctx).Where("supply_order_id = ?", orderID).Order("changed_at, id").Find(&changes).Error
+	return changes, err
+}
+
+func (r *SupplyOrderRepository) OutstandingByItem(ctx context.Context, statuses []string) (map[uint]int, error) {
+	var rows []struct {
+		ItemID   uint
+		Quantity int
+	}
+	err := r.db.WithContext(ctx).Model(&domain.SupplyOrderLine{}).
+		Select("supply_order_lines.item_id, SUM(supply_order_lines.quantity - supply_order_lines.received_quantity) AS quantity").
+		Joins("JOIN supply_orders ON supply_orders.id = supply_order_lines.supply_order_id AND supply_orders.deleted_at IS NULL").
+		Where("supply_orders.status IN ? AND supply_order_lines.item_id != 0", statuses).
+		Group("supply_order_lines.item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		out[row.ItemID] = row.Quantity
+	}
+	return out, nil
 }
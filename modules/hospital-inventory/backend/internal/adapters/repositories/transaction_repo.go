@@ -4,6 +4,7 @@ import (
 	"context"
 	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/ports"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -41,3 +42,56 @@ func (r *GormTransactionRepository) List(ctx context.Context) ([]domain.Inventor
 		Find(&txs).Error
 	return txs, err
 }
+
+func (r *GormTransactionRepository) StreamFiltered(ctx context.Context, filter domain.ExportFilter, fn func(domain.InventoryTransaction) error) error {
+	query := r.db.WithContext(ctx).
+		Preload("Item", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Batch", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		})
+
+	if !filter.From.IsZero() {
+		query = query.Where("timestamp >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("timestamp < ?", filter.To)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("item_id IN (?)", r.db.Unscoped().Model(&domain.Item{}).Select("id").Where("category_id = ?", *filter.CategoryID))
+	}
+
+	// Read in pages so a long ledger is never held in memory at once; pages follow id order
+	var page []domain.InventoryTransaction
+	return query.FindInBatches(&page, 500, func(_ *gorm.DB, _ int) error {
+		for _, tx := range page {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *GormTransactionRepository) SumOutflows(ctx context.Context, from, to time.Time, excludeReasons []string) (map[uint]int, error) {
+	var rows []struct {
+		ItemID   uint
+		Quantity int
+	}
+	query := r.db.WithContext(ctx).Model(&domain.InventoryTransaction{}).
+		Select("item_id, SUM(-quantity_change) AS quantity").
+		Where("quantity_change < 0 AND timestamp >= ? AND timestamp < ?", from, to)
+	if len(excludeReasons) > 0 {
+		query = query.Where("reason NOT IN ?", excludeReasons)
+	}
+	if err := query.Group("item_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		out[row.ItemID] = row.Quantity
+	}
+	return out, nil
+}
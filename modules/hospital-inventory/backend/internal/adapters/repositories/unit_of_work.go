@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"hospital-inventory/internal/core/ports"
+
+	"gorm.io/gorm"
+)
+
+type GormUnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewGormUnitOfWork(db *gorm.DB) ports.UnitOfWork {
+	return &GormUnitOfWork{db: db}
+}
+
+func (u *GormUnitOfWork) Do(ctx context.Context, fn func(repos ports.TxRepositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(ports.TxRepositories{
+			Items:         NewGormItemRepository(tx),
+			Batches:       NewGormBatchRepository(tx),
+			Transactions:  NewGormTransactionRepository(tx),
+			Indents:       NewGormIndentRepository(tx),
+			SupplyOrders:  NewSupplyOrderRepository(tx),
+			GoodsReceipts: NewGormGoodsReceiptRepository(tx),
+		})
+	})
+}
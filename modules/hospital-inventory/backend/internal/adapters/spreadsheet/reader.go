@@ -0,0 +1,220 @@
+// Package spreadsheet reads and writes the tabular files used for bulk import and export.
+// XLSX support covers plain single-sheet workbooks and only needs the standard library.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedFormat is returned for files that are neither CSV nor XLSX.
+var ErrUnsupportedFormat = errors.New("unsupported file format, upload a .csv or .xlsx file")
+
+// ReadTable reads every row of a CSV or XLSX file, choosing the format from the file name.
+// For XLSX only the first worksheet is read.
+func ReadTable(filename string, data []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSV(data)
+	case ".xlsx":
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	// Excel likes to prefix CSV exports with a UTF-8 byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	return r.ReadAll()
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// xlsxRichText is plain (<t>) or rich (<r><t>) text; rich runs are concatenated
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+// maxXLSXRows is the most rows an Excel sheet can have.
+const maxXLSXRows = 1 << 20
+
+type xlsxSheet struct {
+	Rows []struct {
+		// 1-based row number; Excel leaves blank rows out of the file
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx file: missing %s", sheetPath)
+	}
+	var sheet xlsxSheet
+	if err := decodeZipXML(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		if row.R > maxXLSXRows {
+			return nil, fmt.Errorf("invalid xlsx file: row %d is past the end of a sheet", row.R)
+		}
+		for len(rows) < row.R-1 {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				col = columnIndex(cell.Ref)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid xlsx file: bad shared string in %s", cell.Ref)
+				}
+				value = shared.Items[idx].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			}
+			values = append(values, value)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// firstSheetPath resolves the first worksheet listed in the workbook to its part name.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var wb xlsxWorkbook
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("invalid xlsx file: missing workbook")
+	}
+	if err := decodeZipXML(f, &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", errors.New("invalid xlsx file: workbook has no sheets")
+	}
+
+	var rels xlsxRelationships
+	if f, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := decodeZipXML(f, &rels); err != nil {
+			return "", err
+		}
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx file: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex turns a cell reference such as "C7" into a zero-based column index.
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
@@ -0,0 +1,150 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testXLSX zips a minimal workbook around the given sheet data and shared strings.
+func testXLSX(t *testing.T, sheetData string, shared ...string) []byte {
+	t.Helper()
+	var sst strings.Builder
+	sst.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for _, s := range shared {
+		sst.WriteString(s)
+	}
+	sst.WriteString(`</sst>`)
+
+	parts := []struct{ name, body string }{
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Items" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+		{"xl/sharedStrings.xml", sst.String()},
+		{"xl/worksheets/sheet1.xml", `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			sheetData + `</sheetData></worksheet>`},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, p := range parts {
+		w, err := zw.Create(p.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(p.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	tests := []struct {
+		name    string
+		sheet   string
+		shared  []string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:   "shared strings",
+			sheet:  `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>`,
+			shared: []string{`<si><t>name</t></si>`, `<si><r><t>qty</t></r><r><t>_total</t></r></si>`},
+			want:   [][]string{{"name", "qty_total"}},
+		},
+		{
+			name:  "inline strings and numbers",
+			sheet: `<row r="1"><c r="A1" t="inlineStr"><is><t>Gauze</t></is></c><c r="B1"><v>12</v></c></row>`,
+			want:  [][]string{{"Gauze", "12"}},
+		},
+		{
+			name:  "cells without a reference are taken in order",
+			sheet: `<row><c t="inlineStr"><is><t>a</t></is></c><c><v>1</v></c></row><row><c><v>2</v></c></row>`,
+			want:  [][]string{{"a", "1"}, {"2"}},
+		},
+		{
+			name:  "gaps between cells are blank",
+			sheet: `<row r="1"><c r="A1"><v>1</v></c><c r="D1"><v>4</v></c></row>`,
+			want:  [][]string{{"1", "", "", "4"}},
+		},
+		{
+			name:  "rows left out as blank keep the numbering",
+			sheet: `<row r="2"><c r="A2"><v>header</v></c></row><row r="5"><c r="A5"><v>data</v></c></row>`,
+			want:  [][]string{nil, {"header"}, nil, nil, {"data"}},
+		},
+		{
+			name:  "date cells stay serial numbers",
+			sheet: `<row r="1"><c r="A1" s="1"><v>45292</v></c></row>`,
+			want:  [][]string{{"45292"}},
+		},
+		{
+			name:    "shared string out of range",
+			sheet:   `<row r="1"><c r="A1" t="s"><v>3</v></c></row>`,
+			shared:  []string{`<si><t>only</t></si>`},
+			wantErr: "bad shared string in A1",
+		},
+		{
+			name:    "row past the end of a sheet",
+			sheet:   `<row r="1048577"><c r="A1048577"><v>1</v></c></row>`,
+			wantErr: "row 1048577 is past the end of a sheet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadTable("items.XLSX", testXLSX(t, tt.sheet, tt.shared...))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadXLSXInvalid(t *testing.T) {
+	if _, err := ReadTable("items.xlsx", []byte("not a zip")); err == nil || !strings.Contains(err.Error(), "invalid xlsx file") {
+		t.Errorf("error = %v, want an invalid xlsx file error", err)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	data := []byte("\xef\xbb\xbfname, qty\nGauze,12\nSyringe\n")
+	got, err := ReadTable("items.csv", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"name", "qty"}, {"Gauze", "12"}, {"Syringe"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestReadTableUnsupported(t *testing.T) {
+	if _, err := ReadTable("items.xls", nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := map[string]int{"A1": 0, "C7": 2, "Z10": 25, "AA3": 26, "AB1": 27}
+	for ref, want := range tests {
+		if got := columnIndex(ref); got != want {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
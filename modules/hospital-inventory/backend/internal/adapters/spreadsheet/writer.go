@@ -0,0 +1,339 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats accepted by NewWriter.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat is returned by NewWriter for anything but csv, xlsx or json.
+var ErrUnknownFormat = errors.New("format must be csv, xlsx or json")
+
+// Column describes one exported field. Title heads CSV and XLSX sheets, Key names the JSON field.
+type Column struct {
+	Key   string
+	Title string
+}
+
+// Writer streams rows to the underlying io.Writer as they are written.
+// Row values may be string, int, float64, *float64, time.Time or nil. Close must be called to finish the file.
+type Writer interface {
+	WriteRow(values ...interface{}) error
+	Close() error
+}
+
+// ContentType returns the MIME type for an export format.
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewWriter starts a file in the given format and writes its header.
+func NewWriter(w io.Writer, format, sheetName string, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName, columns)
+	case FormatJSON:
+		return newJSONWriter(w, columns), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// hasClock reports whether a time carries a time of day, so dates and timestamps print differently.
+func hasClock(t time.Time) bool {
+	h, m, s := t.Clock()
+	return h != 0 || m != 0 || s != 0
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	titles := make([]string, len(columns))
+	for i, c := range columns {
+		titles[i] = c.Title
+	}
+	return cw, cw.w.Write(titles)
+}
+
+func (cw *csvWriter) WriteRow(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case int:
+			record[i] = strconv.Itoa(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case *float64:
+			if v != nil {
+				record[i] = strconv.FormatFloat(*v, 'f', -1, 64)
+			}
+		case time.Time:
+			if v.IsZero() {
+				break
+			}
+			if hasClock(v) {
+				record[i] = v.Format("2006-01-02 15:04:05")
+			} else {
+				record[i] = v.Format("2006-01-02")
+			}
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type jsonWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	rows    int
+}
+
+func newJSONWriter(w io.Writer, columns []Column) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w), columns: columns}
+}
+
+// WriteRow emits one object with keys in column order, which a map would not preserve.
+func (jw *jsonWriter) WriteRow(values ...interface{}) error {
+	if jw.rows == 0 {
+		jw.w.WriteString("[\n")
+	} else {
+		jw.w.WriteString(",\n")
+	}
+	jw.rows++
+
+	jw.w.WriteByte('{')
+	for i, c := range jw.columns {
+		if i > 0 {
+			jw.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(c.Key)
+		jw.w.Write(key)
+		jw.w.WriteByte(':')
+
+		var v interface{}
+		if i < len(values) {
+			v = values[i]
+		}
+		if t, ok := v.(time.Time); ok && t.IsZero() {
+			v = nil
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		jw.w.Write(value)
+	}
+	_, err := jw.w.WriteString("}")
+	return err
+}
+
+func (jw *jsonWriter) Close() error {
+	if jw.rows == 0 {
+		jw.w.WriteString("[")
+	}
+	jw.w.WriteString("\n]\n")
+	return jw.w.Flush()
+}
+
+// Cell styles declared in xlsxStyles
+const (
+	styleDate     = 1
+	styleDateTime = 2
+	styleHeader   = 3
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs></styleSheet>`
+
+// xlsxWriter writes a single-sheet workbook. Every part except the sheet is written up front,
+// so rows go straight into the zip stream without being held in memory.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXWriter(w io.Writer, sheetName string, columns []Column) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheetName))
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &xlsxWriter{zw: zw, sheet: bufio.NewWriter(f)}
+	xw.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	titles := make([]interface{}, len(columns))
+	for i, c := range columns {
+		titles[i] = c.Title
+	}
+	return xw, xw.writeRow(titles, styleHeader)
+}
+
+func (xw *xlsxWriter) WriteRow(values ...interface{}) error {
+	return xw.writeRow(values, 0)
+}
+
+func (xw *xlsxWriter) writeRow(values []interface{}, style int) error {
+	xw.row++
+	fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.row)
+	for i, v := range values {
+		ref := columnName(i) + strconv.Itoa(xw.row)
+		if p, ok := v.(*float64); ok {
+			if p == nil {
+				continue
+			}
+			v = *p
+		}
+
+		switch v := v.(type) {
+		case nil:
+		case int:
+			fmt.Fprintf(xw.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(xw.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			s := styleDate
+			if hasClock(v) {
+				s = styleDateTime
+			}
+			fmt.Fprintf(xw.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, s, strconv.FormatFloat(excelSerial(v), 'f', -1, 64))
+		default:
+			text := fmt.Sprint(v)
+			if text == "" {
+				continue
+			}
+			fmt.Fprintf(xw.sheet, `<c r="%s" t="inlineStr"`, ref)
+			if style != 0 {
+				fmt.Fprintf(xw.sheet, ` s="%d"`, style)
+			}
+			xw.sheet.WriteString(`><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(xw.sheet, []byte(text)); err != nil {
+				return err
+			}
+			xw.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := xw.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (xw *xlsxWriter) Close() error {
+	xw.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zw.Close()
+}
+
+// excelSerial converts a time to the fractional day count spreadsheets use for dates.
+func excelSerial(t time.Time) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	days := wall.Sub(epoch).Hours() / 24
+	// Round to the second so serials do not carry float noise
+	return float64(int64(days*86400+0.5)) / 86400
+}
+
+// columnName turns a zero-based column index into its letter name: 0 -> A, 27 -> AB.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,149 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testColumns = []Column{
+	{Key: "name", Title: "Item"},
+	{Key: "quantity", Title: "Quantity"},
+	{Key: "mrp", Title: "MRP"},
+	{Key: "expiry", Title: "Expiry Date"},
+	{Key: "timestamp", Title: "Timestamp"},
+	{Key: "notes", Title: "Notes"},
+}
+
+// testRows cover every value type a Writer accepts, blanks included.
+func testRows() [][]interface{} {
+	mrp := 12.5
+	return [][]interface{}{
+		{"Paracetamol <500mg> & co", 120, &mrp, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), "quoted \"notes\", with commas"},
+		{"Gauze", -3, (*float64)(nil), time.Time{}, nil, ""},
+		{"Syringe", 0, 0.1, time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 6, 30, 15, 0, time.UTC), "two\nlines"},
+	}
+}
+
+func writeTestFile(t *testing.T, format string, columns []Column, rows [][]interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, "Stock & Co", columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		want   [][]string
+	}{
+		{
+			format: FormatCSV,
+			want: [][]string{
+				{"Item", "Quantity", "MRP", "Expiry Date", "Timestamp", "Notes"},
+				{"Paracetamol <500mg> & co", "120", "12.5", "2024-01-31", "2024-01-31 12:00:00", "quoted \"notes\", with commas"},
+				{"Gauze", "-3", "", "", "", ""},
+				{"Syringe", "0", "0.1", "2030-06-01", "2024-02-01 06:30:15", "two\nlines"},
+			},
+		},
+		{
+			// Blank cells are left out of the sheet, so short rows come back short; dates are serials
+			format: FormatXLSX,
+			want: [][]string{
+				{"Item", "Quantity", "MRP", "Expiry Date", "Timestamp", "Notes"},
+				{"Paracetamol <500mg> & co", "120", "12.5", "45322", "45322.5", "quoted \"notes\", with commas"},
+				{"Gauze", "-3"},
+				{"Syringe", "0", "0.1", "47635", fmt.Sprint(45323 + (6*3600+30*60+15)/86400.0), "two\nlines"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			data := writeTestFile(t, tt.format, testColumns, testRows())
+			got, err := ReadTable("export."+tt.format, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read back %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterRoundTripWideSheet(t *testing.T) {
+	var columns []Column
+	var row []interface{}
+	var want []string
+	for i := 0; i < 30; i++ {
+		columns = append(columns, Column{Key: fmt.Sprint(i), Title: columnName(i)})
+		row = append(row, i)
+		want = append(want, fmt.Sprint(i))
+	}
+	got, err := ReadTable("wide.xlsx", writeTestFile(t, FormatXLSX, columns, [][]interface{}{row}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0][26] != "AA" || got[0][29] != "AD" || !reflect.DeepEqual(got[1], want) {
+		t.Errorf("read back %q", got)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	data := writeTestFile(t, FormatJSON, testColumns, testRows())
+	var got []map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d objects, want 3", len(got))
+	}
+	if got[0]["name"] != "Paracetamol <500mg> & co" || got[0]["quantity"] != 120.0 || got[0]["mrp"] != 12.5 {
+		t.Errorf("first object = %v", got[0])
+	}
+	if got[1]["mrp"] != nil || got[1]["expiry"] != nil || got[1]["timestamp"] != nil {
+		t.Errorf("blank values = %v, want null", got[1])
+	}
+	// Keys keep column order
+	if !bytes.HasPrefix(data, []byte(`[`+"\n"+`{"name":`)) || !bytes.Contains(data, []byte(`"timestamp":null,"notes":""}`)) {
+		t.Errorf("keys out of column order: %s", data)
+	}
+
+	empty := writeTestFile(t, FormatJSON, testColumns, nil)
+	if string(empty) != "[\n]\n" {
+		t.Errorf("empty export = %q, want an empty array", empty)
+	}
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "xls", "Stock", testColumns); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("error = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+		if back := columnIndex(want + "1"); back != i {
+			t.Errorf("columnIndex(%q) = %d, want %d", want+"1", back, i)
+		}
+	}
+}
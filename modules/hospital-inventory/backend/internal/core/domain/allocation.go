@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AllocationStrategy decides which batches an indent line draws its stock from first.
+type AllocationStrategy interface {
+	// Name is how the strategy is configured and shown, e.g. "FEFO" or "LOCATION:Rack A".
+	Name() string
+	// Sort puts the batches in the order they should be taken.
+	Sort(batches []Batch)
+}
+
+// FEFO takes the earliest expiring batches first, the oldest received among equal dates.
+type FEFO struct{}
+
+func (FEFO) Name() string { return "FEFO" }
+
+func (FEFO) Sort(batches []Batch) {
+	sort.SliceStable(batches, func(i, j int) bool {
+		if !batches[i].ExpiryDate.Equal(batches[j].ExpiryDate) {
+			return batches[i].ExpiryDate.Before(batches[j].ExpiryDate)
+		}
+		return batches[i].CreatedAt.Before(batches[j].CreatedAt)
+	})
+}
+
+// FIFO takes the batches received first, whatever their expiry.
+type FIFO struct{}
+
+func (FIFO) Name() string { return "FIFO" }
+
+func (FIFO) Sort(batches []Batch) {
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].CreatedAt.Before(batches[j].CreatedAt)
+	})
+}
+
+// LocationFirst empties the batches kept at Location before any other, each group in FEFO order.
+type LocationFirst struct {
+	Location string
+}
+
+func (l LocationFirst) Name() string { return "LOCATION:" + l.Location }
+
+func (l LocationFirst) Sort(batches []Batch) {
+	FEFO{}.Sort(batches)
+	sort.SliceStable(batches, func(i, j int) bool {
+		return l.at(&batches[i]) && !l.at(&batches[j])
+	})
+}
+
+func (l LocationFirst) at(b *Batch) bool {
+	return strings.EqualFold(strings.TrimSpace(b.Location), l.Location)
+}
+
+// ParseAllocationStrategy reads a strategy name: FEFO, FIFO or LOCATION:<location>, case-insensitive.
+func ParseAllocationStrategy(name string) (AllocationStrategy, error) {
+	name = strings.TrimSpace(name)
+	kind, location, _ := strings.Cut(name, ":")
+	switch strings.ToUpper(strings.TrimSpace(kind)) {
+	case "FEFO":
+		return FEFO{}, nil
+	case "FIFO":
+		return FIFO{}, nil
+	case "LOCATION":
+		location = strings.TrimSpace(location)
+		if location == "" {
+			return nil, fmt.Errorf("%w: LOCATION allocation needs a location, e.g. LOCATION:Rack A", ErrValidation)
+		}
+		return LocationFirst{Location: location}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown allocation strategy %q, use FEFO, FIFO or LOCATION:<location>", ErrValidation, name)
+}
+
+// MinShelfLife is the number of days a batch of the item must still be good for when it leaves
+// for the pharmacy. The item's own setting wins over its category's.
+func (i *Item) MinShelfLife() int {
+	if i.MinShelfLifeDays != nil {
+		return *i.MinShelfLifeDays
+	}
+	return i.Category.MinShelfLifeDays
+}
+
+// ShelfLifeCutoff is the date a batch of the item must expire after to be dispatched at now.
+func (i *Item) ShelfLifeCutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, i.MinShelfLife())
+}
+
+// Dispatchable reports whether the batch is still good at cutoff.
+// Batches without an expiry date never run out.
+func (b *Batch) Dispatchable(cutoff time.Time) bool {
+	return b.ExpiryDate.IsZero() || b.ExpiryDate.After(cutoff)
+}
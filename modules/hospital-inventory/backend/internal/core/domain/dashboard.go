@@ -3,6 +3,18 @@ package domain
 type DashboardStats struct {
 	TotalItems    int64   `json:"total_items"`
 	TotalValue    float64 `json:"total_value"`
+	LowStockItems int64   `json:"low_stock_items"` // Items whose batches add up to less than their threshold
+	ExpiredItems  int64   `json:"expired_items"`
+
+	Categories []CategoryStats `json:"categories"`
+}
+
+// CategoryStats breaks the dashboard figures down by item category.
+type CategoryStats struct {
+	CategoryID    *uint   `json:"category_id"` // nil for uncategorized items
+	Name          string  `json:"name"`
+	ItemCount     int64   `json:"item_count"`
+	StockValue    float64 `json:"stock_value"`
 	LowStockItems int64   `json:"low_stock_items"`
 	ExpiredItems  int64   `json:"expired_items"`
 }
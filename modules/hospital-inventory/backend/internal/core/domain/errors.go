@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+// Sentinel errors returned by services. Wrap them with detail using fmt.Errorf("%w: ...")
+// so handlers can map them to status codes with errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	// ErrUnprocessable is a well-formed request the current data cannot satisfy, e.g. a status that does not exist.
+	ErrUnprocessable = errors.New("unprocessable")
+)
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// ExportFilter narrows an export. Zero times leave that end of the range open.
+type ExportFilter struct {
+	From       time.Time
+	To         time.Time
+	CategoryID *uint
+}
+
+// StockRow is one batch of stock as it appears in an export.
+type StockRow struct {
+	ItemID      uint      `json:"item_id"`
+	ItemName    string    `json:"item_name"`
+	Category    string    `json:"category"`
+	Unit        string    `json:"unit"`
+	BatchID     uint      `json:"batch_id"`
+	BatchNumber string    `json:"batch_number"`
+	ExpiryDate  time.Time `json:"expiry_date"`
+	Quantity    int       `json:"quantity"`
+	MRP         *float64  `json:"mrp"`
+	Value       float64   `json:"value"` // Quantity x MRP
+	Location    string    `json:"location"`
+	Supplier    string    `json:"supplier"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+// ImportRowError describes a problem with one row of an import file.
+// Row is the spreadsheet row number, so the header is row 1.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport is the outcome of validating (and, unless dry run, committing) an import file.
+type ImportReport struct {
+	DryRun         bool             `json:"dry_run"`
+	Committed      bool             `json:"committed"`
+	TotalRows      int              `json:"total_rows"`
+	ItemsCreated   int              `json:"items_created"`
+	BatchesCreated int              `json:"batches_created"`
+	Errors         []ImportRowError `json:"errors"`
+	// Warnings are columns that were read but not applied; they do not stop the import
+	Warnings []ImportRowError `json:"warnings"`
+}
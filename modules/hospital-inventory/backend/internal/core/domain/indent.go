@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Indent statuses. See indentTransitions for the allowed moves.
+const (
+	IndentStatusPending             = "PENDING"
+	IndentStatusProcessing          = "PROCESSING"
+	IndentStatusDispatched          = "DISPATCHED"
+	IndentStatusPartiallyDispatched = "PARTIALLY_DISPATCHED"
+	IndentStatusFulfilled           = "FULFILLED"
+	IndentStatusRejected            = "REJECTED"
+)
+
+// indentTransitions lists the statuses each status may move to. Once stock has left the
+// hospital store an indent can no longer be rejected, only confirmed by the pharmacy.
+// Whether a dispatch is full or partial is decided by what was picked, see DispatchStatus.
+var indentTransitions = map[string][]string{
+	IndentStatusPending:             {IndentStatusProcessing, IndentStatusRejected},
+	IndentStatusProcessing:          {IndentStatusDispatched, IndentStatusPartiallyDispatched, IndentStatusRejected},
+	IndentStatusDispatched:          {IndentStatusFulfilled},
+	IndentStatusPartiallyDispatched: {IndentStatusFulfilled},
+	IndentStatusFulfilled:           {},
+	IndentStatusRejected:            {},
+}
+
+// ErrUnknownIndentStatus is returned for a status outside the indent lifecycle.
+var ErrUnknownIndentStatus = fmt.Errorf("%w: unknown indent status", ErrUnprocessable)
+
+// IndentTransitionError reports a status change the indent lifecycle does not allow.
+// LineID is set when a single line was asked to move. It matches ErrConflict with errors.Is.
+type IndentTransitionError struct {
+	IndentID uint
+	LineID   uint
+	From     string
+	To       string
+}
+
+func (e *IndentTransitionError) Error() string {
+	subject := fmt.Sprintf("indent %d", e.IndentID)
+	if e.LineID != 0 {
+		subject += fmt.Sprintf(" line %d", e.LineID)
+	}
+	allowed := indentTransitions[e.From]
+	if len(allowed) == 0 {
+		return fmt.Sprintf("%v: %s is %s and can no longer change", ErrConflict, subject, e.From)
+	}
+	return fmt.Sprintf("%v: %s cannot move from %s to %s, only to %s",
+		ErrConflict, subject, e.From, e.To, strings.Join(allowed, " or "))
+}
+
+func (e *IndentTransitionError) Unwrap() error {
+	return ErrConflict
+}
+
+// NormalizeIndentStatus upper-cases status and checks it is part of the lifecycle.
+func NormalizeIndentStatus(status string) (string, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if _, ok := indentTransitions[status]; !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownIndentStatus, status)
+	}
+	return status, nil
+}
+
+// IsDispatch reports whether status is one of the two dispatch outcomes.
+func IsDispatch(status string) bool {
+	return status == IndentStatusDispatched || status == IndentStatusPartiallyDispatched
+}
+
+// CanTransitionTo reports whether the line may move to status. The two dispatch outcomes
+// are interchangeable here, which one applies is only known once the picks are counted.
+func (l *IndentLine) CanTransitionTo(status string) bool {
+	for _, next := range indentTransitions[l.Status] {
+		if next == status || (IsDispatch(next) && IsDispatch(status)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns an *IndentTransitionError unless the line may move to status.
+func (l *IndentLine) CheckTransition(status string) error {
+	if l.CanTransitionTo(status) {
+		return nil
+	}
+	return &IndentTransitionError{IndentID: l.IndentID, LineID: l.ID, From: l.Status, To: status}
+}
+
+// Shortfall is how much of the line's request has not been dispatched.
+func (l *IndentLine) Shortfall() int {
+	if l.DispatchedQuantity >= l.Quantity {
+		return 0
+	}
+	return l.Quantity - l.DispatchedQuantity
+}
+
+// DispatchStatus is DISPATCHED when the full request went out and PARTIALLY_DISPATCHED otherwise.
+func (l *IndentLine) DispatchStatus() string {
+	if l.Shortfall() > 0 {
+		return IndentStatusPartiallyDispatched
+	}
+	return IndentStatusDispatched
+}
+
+// Line returns the indent's line with the given id.
+func (i *Indent) Line(id uint) (*IndentLine, error) {
+	for k := range i.Lines {
+		if i.Lines[k].ID == id {
+			return &i.Lines[k], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: indent %d has no line %d", ErrNotFound, i.ID, id)
+}
+
+// indentStage orders statuses by how far a line has got; rejected lines are left out.
+var indentStage = map[string]int{
+	IndentStatusPending:             0,
+	IndentStatusProcessing:          1,
+	IndentStatusPartiallyDispatched: 2,
+	IndentStatusDispatched:          2,
+	IndentStatusFulfilled:           3,
+}
+
+// Summarize refreshes the indent's own fields from its lines. The status is that of the
+// line furthest behind, so the indent only moves on once every line has, and the quantities
+// are totals. Dispatch details are only copied from a single line, which is the shape
+// the pharmacy reads; an indent with several lines carries them on the lines.
+func (i *Indent) Summarize() {
+	i.Quantity, i.ApprovedQuantity, i.DispatchedQuantity = 0, 0, 0
+	names := make([]string, 0, len(i.Lines))
+	status, stage, partial := IndentStatusRejected, len(indentStage), false
+	for _, l := range i.Lines {
+		i.Quantity += l.Quantity
+		i.ApprovedQuantity += l.ApprovedQuantity
+		i.DispatchedQuantity += l.DispatchedQuantity
+		names = append(names, l.ItemName)
+
+		st, ok := indentStage[l.Status]
+		if !ok {
+			continue
+		}
+		if l.Status == IndentStatusPartiallyDispatched {
+			partial = true
+		}
+		if st < stage {
+			status, stage = l.Status, st
+		}
+	}
+	if IsDispatch(status) && partial {
+		status = IndentStatusPartiallyDispatched
+	}
+	i.Status = status
+	i.ItemName = strings.Join(names, ", ")
+	i.DispatchDetails = ""
+	if len(i.Lines) == 1 {
+		i.DispatchDetails = i.Lines[0].DispatchDetails
+	}
+}
+
+// IndentPick is one line of a storekeeper's pick list: take Quantity from the batch.
+type IndentPick struct {
+	BatchID  uint `json:"batch_id"`
+	Quantity int  `json:"quantity"`
+}
+
+// PickList is what a PROCESSING indent line holds, together with the item's other batches it could be picked from.
+// Candidates leave out batches too close to expiry and come in the order the allocation strategy takes them.
+type PickList struct {
+	Picks            []IndentReservation `json:"picks"`
+	Candidates       []Batch             `json:"candidates"`
+	Strategy         string              `json:"strategy"`
+	MinShelfLifeDays int                 `json:"min_shelf_life_days"`
+}
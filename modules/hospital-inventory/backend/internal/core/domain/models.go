@@ -18,7 +18,9 @@ type Category struct {
 	BaseModel
 	Name        string `gorm:"unique;not null" json:"name"`
 	Description string `json:"description"`
-	Items       []Item `json:"-"` // One-to-many
+	// Days a batch must still be good for to be dispatched to the pharmacy, unless the item says otherwise
+	MinShelfLifeDays int    `json:"min_shelf_life_days" gorm:"not null;default:0"`
+	Items            []Item `json:"-"` // One-to-many
 }
 
 type Item struct {
@@ -31,7 +33,9 @@ type Item struct {
 	Unit         string   `json:"unit"`          // e.g., "Tablets", "Vials"
 	Price        float64  `json:"price"`         // Base price (shared schema)
 	RestockLevel int      `json:"restock_level"` // Suggested reorder quantity
-	Batches      []Batch  `json:"batches"`
+	// Overrides the category's minimum shelf life when set, see MinShelfLife
+	MinShelfLifeDays *int    `json:"min_shelf_life_days"`
+	Batches          []Batch `json:"batches"`
 
 	// Calculated fields (handled at runtime/query time)
 	TotalQuantity int `json:"total_quantity" gorm:"-"`
@@ -39,14 +43,42 @@ type Item struct {
 
 type Batch struct {
 	BaseModel
-	ItemID        uint      `json:"item_id" gorm:"index"`
-	BatchNumber   string    `json:"batch_number" gorm:"index"`
-	Quantity      int       `json:"quantity"` // Current stock in this batch
-	MRP           *float64  `json:"mrp"`
-	PurchasePrice float64   `json:"purchase_price"`           // Good to track cost vs MRP
-	ExpiryDate    time.Time `json:"expiry_date" gorm:"index"` // Index for quick expiry lookups
-	Location      string    `json:"location"`                 // Rack/Shelf ID
-	SupplierID    *uint     `json:"supplier_id"`
+	ItemID      uint   `json:"item_id" gorm:"index"`
+	BatchNumber string `json:"batch_number" gorm:"index"`
+	Quantity    int    `json:"quantity"` // Current stock in this batch
+	// Held for indents being processed, only changed through BatchRepository.Reserve and friends.
+	// Quantity - ReservedQuantity is free to allocate.
+	ReservedQuantity int       `json:"reserved_quantity" gorm:"not null;default:0"`
+	MRP              *float64  `json:"mrp"`
+	PurchasePrice    float64   `json:"purchase_price"`           // Good to track cost vs MRP
+	ExpiryDate       time.Time `json:"expiry_date" gorm:"index"` // Index for quick expiry lookups
+	Location         string    `json:"location"`                 // Rack/Shelf ID
+	SupplierID       *uint     `json:"supplier_id" gorm:"index"`
+	Supplier         *Supplier `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+}
+
+// BatchUpdate is a manual edit of a batch. SupplierID and PurchasePrice keep their current
+// value when nil, so a form that does not show them cannot wipe them.
+type BatchUpdate struct {
+	BatchNumber   string
+	Quantity      int
+	ExpiryDate    time.Time
+	Location      string
+	MRP           *float64
+	SupplierID    *uint // 0 clears the supplier
+	PurchasePrice *float64
+}
+
+type Supplier struct {
+	BaseModel
+	Name          string `gorm:"not null;index" json:"name"`
+	ContactPerson string `json:"contact_person"`
+	Phone         string `json:"phone"`
+	Email         string `json:"email"`
+	Address       string `json:"address"`
+	GSTIN         string `gorm:"column:gstin;index" json:"gstin"` // 15 character GST registration number
+	LeadTimeDays  int    `json:"lead_time_days"`                  // Days from order to delivery
+	PaymentTerms  string `json:"payment_terms"`                   // e.g. "Net 30", "Advance"
 }
 
 // InventoryTransaction is an immutable ledger of all stock movements.
@@ -85,18 +117,129 @@ type EmergencyRequest struct {
 
 type Indent struct {
 	BaseModel
-	ItemName        string `json:"item_name"`
-	Quantity        int    `json:"quantity"`
-	Status          string `json:"status" gorm:"default:'PENDING'"` // PENDING, PROCESSING, DISPATCHED, FULFILLED, REJECTED
-	PharmacyID      string `json:"pharmacy_id"`                     // Identifier for the pharmacy
-	DispatchDetails string `json:"dispatch_details"`                // JSON or formatted string of suggested batches
+	// ItemName down to DispatchDetails summarise Lines, see Summarize. They are kept for
+	// single-item clients such as the pharmacy.
+	ItemName           string       `json:"item_name"`
+	Quantity           int          `json:"quantity"`                             // Requested by the pharmacy
+	ApprovedQuantity   int          `json:"approved_quantity" gorm:"default:0"`   // Picked by the store, set when processing
+	DispatchedQuantity int          `json:"dispatched_quantity" gorm:"default:0"` // Actually sent, set on dispatch
+	Status             string       `json:"status" gorm:"default:'PENDING'"`      // PENDING, PROCESSING, DISPATCHED, PARTIALLY_DISPATCHED, FULFILLED, REJECTED
+	PharmacyID         string       `json:"pharmacy_id"`                          // Identifier for the pharmacy
+	DispatchDetails    string       `json:"dispatch_details"`                     // JSON or formatted string of suggested batches
+	BackorderOfID      *uint        `json:"backorder_of_id"`                      // Set on the indent raised for another's shortfall
+	Lines              []IndentLine `json:"lines" gorm:"foreignKey:IndentID"`
+}
+
+// IndentLine is one item of an indent. Each line is picked, dispatched and moves through
+// the indent lifecycle on its own.
+type IndentLine struct {
+	ID                 uint   `gorm:"primaryKey" json:"id"`
+	IndentID           uint   `json:"indent_id" gorm:"index"`
+	ItemName           string `json:"item_name"`
+	Quantity           int    `json:"quantity"`
+	ApprovedQuantity   int    `json:"approved_quantity" gorm:"default:0"`
+	DispatchedQuantity int    `json:"dispatched_quantity" gorm:"default:0"`
+	Status             string `json:"status" gorm:"default:'PENDING'"`
+	DispatchDetails    string `json:"dispatch_details"`
 }
 
+// IndentReservation holds stock of one batch for an indent line between PROCESSING and dispatch.
+type IndentReservation struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	IndentID     uint      `json:"indent_id" gorm:"index"`
+	IndentLineID uint      `json:"indent_line_id" gorm:"index"`
+	BatchID      uint      `json:"batch_id" gorm:"index"`
+	Batch        *Batch    `json:"batch,omitempty" gorm:"foreignKey:BatchID"`
+	Quantity     int       `json:"quantity"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// IndentStatusChange is the audit trail of an indent's lifecycle, one row per transition.
+type IndentStatusChange struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	IndentID   uint      `json:"indent_id" gorm:"index"`
+	LineID     *uint     `json:"line_id"`     // Nil for changes to the indent as a whole
+	FromStatus string    `json:"from_status"` // Empty for the indent's creation
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Note       string    `json:"note"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// Supply order statuses. See orderTransitions for the allowed moves.
+const (
+	OrderStatusDraft             = "Draft"
+	OrderStatusApproved          = "Approved"
+	OrderStatusSent              = "Sent"
+	OrderStatusPartiallyReceived = "Partially Received"
+	OrderStatusReceived          = "Received"
+	OrderStatusCancelled         = "Cancelled"
+
+	// OrderStatusPending predates the lifecycle; such orders are migrated to Sent.
+	OrderStatusPending = "Pending"
+)
+
+// SupplyOrder is a purchase order to one supplier. Lines replaced the old items JSON column,
+// which database.migrateSupplyOrderItems converts on startup.
 type SupplyOrder struct {
 	BaseModel
-	SupplierName string    `json:"supplier_name"`
-	Status       string    `json:"status" gorm:"default:'Pending'"` // Pending, Received, Cancelled
+	SupplierID   *uint     `json:"supplier_id" gorm:"index"`
+	Supplier     *Supplier `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	SupplierName string    `json:"supplier_name"`                 // Copied from the supplier when the order is placed
+	Status       string    `json:"status" gorm:"default:'Draft'"` // Draft, Approved, Sent, Partially Received, Received, Cancelled
 	OrderDate    time.Time `json:"order_date"`
-	Items        string    `json:"items"` // JSON blob: [{itemId, itemName, quantity, unitCost, total}]
-	TotalCost    float64   `json:"total_cost"`
+	Subtotal     float64   `json:"subtotal" gorm:"default:0"`  // Sum of quantity x unit cost, before tax
+	TaxTotal     float64   `json:"tax_total" gorm:"default:0"` // Sum of line taxes
+	TotalCost    float64   `json:"total_cost"`                 // Subtotal + tax, computed by the server
+
+	Lines []SupplyOrderLine `json:"lines"`
+}
+
+// SupplyOrderLine is one item on a supply order. Amounts are computed by SupplyOrderLine.ComputeTotals.
+type SupplyOrderLine struct {
+	ID               uint    `gorm:"primaryKey" json:"id"`
+	SupplyOrderID    uint    `json:"supply_order_id" gorm:"index"`
+	ItemID           uint    `json:"item_id" gorm:"index"` // 0 only for migrated rows that named no known item
+	ItemName         string  `json:"item_name"`            // Copied from the item when the order is placed
+	Quantity         int     `json:"quantity"`
+	UnitCost         float64 `json:"unit_cost"`
+	TaxRate          float64 `json:"tax_rate"` // GST percent
+	TaxAmount        float64 `json:"tax_amount"`
+	LineTotal        float64 `json:"line_total"`
+	ReceivedQuantity int     `json:"received_quantity"` // Booked so far through goods receipts
+}
+
+// SupplyOrderStatusChange is the audit trail of a supply order's lifecycle, one row per transition.
+type SupplyOrderStatusChange struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	SupplyOrderID uint      `json:"supply_order_id" gorm:"index"`
+	FromStatus    string    `json:"from_status"` // Empty for the order's creation
+	ToStatus      string    `json:"to_status"`
+	Actor         string    `json:"actor"`
+	Note          string    `json:"note"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// GoodsReceipt (GRN) records one delivery against a supply order. An order may be received over several GRNs.
+type GoodsReceipt struct {
+	BaseModel
+	ReceiptNumber string             `json:"receipt_number" gorm:"index"`
+	SupplyOrderID uint               `json:"supply_order_id" gorm:"index"`
+	ReceivedBy    string             `json:"received_by"`
+	ReceivedAt    time.Time          `json:"received_at"`
+	Notes         string             `json:"notes"`
+	Lines         []GoodsReceiptLine `json:"lines"`
+}
+
+type GoodsReceiptLine struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	GoodsReceiptID uint      `json:"goods_receipt_id" gorm:"index"`
+	ItemID         uint      `json:"item_id" gorm:"index"`
+	BatchID        uint      `json:"batch_id"` // Batch the stock was booked into
+	BatchNumber    string    `json:"batch_number"`
+	ExpiryDate     time.Time `json:"expiry_date"`
+	Quantity       int       `json:"quantity"`
+	PurchasePrice  float64   `json:"purchase_price"`
+	MRP            *float64  `json:"mrp"`
+	Location       string    `json:"location"`
 }
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Reorder defaults, used when a request leaves a parameter out.
+const (
+	DefaultReorderWindowDays = 30 // Days of consumption history averaged
+	DefaultSafetyDays        = 7  // Days of average usage held back as safety stock
+	DefaultCoverDays         = 30 // Days of usage a new order should last once it arrives
+	DefaultLeadTimeDays      = 7  // Used for items with no supplier, or a supplier with no lead time set
+)
+
+// ReorderParams tune the suggestion maths. Zero window or cover days, and negative safety days,
+// take the defaults above.
+type ReorderParams struct {
+	WindowDays int
+	SafetyDays int
+	CoverDays  int
+}
+
+// ReorderSuggestion is one item that has fallen to its reorder point. ItemID, Quantity and
+// UnitCost line up with a supply order line, so a suggestion can be posted as is.
+type ReorderSuggestion struct {
+	ItemID        uint     `json:"item_id"`
+	ItemName      string   `json:"item_name"`
+	Unit          string   `json:"unit"`
+	CurrentStock  int      `json:"current_stock"` // Unexpired stock not reserved for dispatch
+	OnOrder       int      `json:"on_order"`      // Ordered but not yet received
+	Consumed      int      `json:"consumed"`      // Outflow over the window
+	AvgDailyUsage float64  `json:"avg_daily_usage"`
+	DaysOfCover   *float64 `json:"days_of_cover"` // (stock + on order) / daily usage, null when nothing is used
+	LeadTimeDays  int      `json:"lead_time_days"`
+	SafetyStock   int      `json:"safety_stock"`
+	ReorderPoint  int      `json:"reorder_point"`
+	Quantity      int      `json:"quantity"` // Suggested order quantity
+	UnitCost      float64  `json:"unit_cost"`
+	Reason        string   `json:"reason"`
+}
+
+// SupplierReorder groups suggestions by the supplier that last delivered each item.
+type SupplierReorder struct {
+	SupplierID    *uint               `json:"supplier_id"` // Nil for items never received from a known supplier
+	SupplierName  string              `json:"supplier_name"`
+	LeadTimeDays  int                 `json:"lead_time_days"`
+	EstimatedCost float64             `json:"estimated_cost"`
+	Items         []ReorderSuggestion `json:"items"`
+}
+
+type ReorderReport struct {
+	From       time.Time         `json:"from"`
+	To         time.Time         `json:"to"`
+	WindowDays int               `json:"window_days"`
+	SafetyDays int               `json:"safety_days"`
+	CoverDays  int               `json:"cover_days"`
+	Suppliers  []SupplierReorder `json:"suppliers"`
+}
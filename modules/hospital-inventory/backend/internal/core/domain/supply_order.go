@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// LegacyOrderItem is one entry of the JSON blob supply orders used to keep in their items column.
+// Manually keyed rows carry a client timestamp as item_id, so the name is the reliable key.
+type LegacyOrderItem struct {
+	ItemID   float64 `json:"item_id"`
+	ItemName string  `json:"item_name"`
+	Quantity int     `json:"quantity"`
+	UnitCost float64 `json:"unit_cost"`
+}
+
+// ParseLegacyOrderItems reads an old items blob: [{item_id, item_name, quantity, unit_cost, total}].
+func ParseLegacyOrderItems(blob string) ([]LegacyOrderItem, error) {
+	var items []LegacyOrderItem
+	if blob == "" {
+		return items, nil
+	}
+	err := json.Unmarshal([]byte(blob), &items)
+	return items, err
+}
+
+// orderTransitions lists the statuses each status may move to. Partially Received and Received
+// are only reached by recording goods receipts.
+var orderTransitions = map[string][]string{
+	OrderStatusDraft:             {OrderStatusApproved, OrderStatusCancelled},
+	OrderStatusApproved:          {OrderStatusSent, OrderStatusCancelled},
+	OrderStatusSent:              {OrderStatusPartiallyReceived, OrderStatusReceived, OrderStatusCancelled},
+	OrderStatusPartiallyReceived: {OrderStatusPartiallyReceived, OrderStatusReceived, OrderStatusCancelled},
+	OrderStatusReceived:          {},
+	OrderStatusCancelled:         {},
+}
+
+// IsOrderStatus reports whether status is part of the supply order lifecycle.
+func IsOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransitionTo reports whether the order may move from its current status to status.
+func (o *SupplyOrder) CanTransitionTo(status string) bool {
+	for _, next := range orderTransitions[o.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReceivable reports whether goods may be received against the order.
+func (o *SupplyOrder) IsReceivable() bool {
+	return o.Status == OrderStatusSent || o.Status == OrderStatusPartiallyReceived
+}
+
+// PONumber is the order's document number, printed on the purchase order and quoted on its receipts.
+func (o *SupplyOrder) PONumber() string {
+	return fmt.Sprintf("PO-%06d", o.ID)
+}
+
+// ComputeTotals prices the line: quantity x unit cost, plus tax at TaxRate percent.
+func (l *SupplyOrderLine) ComputeTotals() {
+	net := float64(l.Quantity) * l.UnitCost
+	l.TaxAmount = RoundMoney(net * l.TaxRate / 100)
+	l.LineTotal = RoundMoney(net + l.TaxAmount)
+}
+
+// ComputeTotals prices every line and rolls the amounts up to the order.
+func (o *SupplyOrder) ComputeTotals() {
+	o.Subtotal, o.TaxTotal, o.TotalCost = 0, 0, 0
+	for i := range o.Lines {
+		l := &o.Lines[i]
+		l.ComputeTotals()
+		o.Subtotal += float64(l.Quantity) * l.UnitCost
+		o.TaxTotal += l.TaxAmount
+		o.TotalCost += l.LineTotal
+	}
+	o.Subtotal = RoundMoney(o.Subtotal)
+	o.TaxTotal = RoundMoney(o.TaxTotal)
+	o.TotalCost = RoundMoney(o.TotalCost)
+}
+
+// RoundMoney rounds to paise.
+func RoundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
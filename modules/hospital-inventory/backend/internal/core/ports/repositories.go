@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"hospital-inventory/internal/core/domain"
+	"time"
 )
 
 type ItemRepository interface {
@@ -21,12 +22,25 @@ type BatchRepository interface {
 	GetByID(ctx context.Context, id uint) (*domain.Batch, error)
 	Delete(ctx context.Context, id uint) error
 	GetByItemID(ctx context.Context, itemID uint) ([]domain.Batch, error)
+	// StreamStock calls fn for every batch in stock, filtered by expiry date and item category.
+	StreamStock(ctx context.Context, filter domain.ExportFilter, fn func(domain.StockRow) error) error
+	// Reserve holds qty of the batch. It fails with domain.ErrConflict when less than qty is unreserved,
+	// so two callers can never hold the same stock.
+	Reserve(ctx context.Context, batchID uint, qty int) error
+	// Release returns reserved stock to the free pool.
+	Release(ctx context.Context, batchID uint, qty int) error
+	// ConsumeReserved takes qty out of both the stock and the reservation of the batch.
+	ConsumeReserved(ctx context.Context, batchID uint, qty int) error
 }
 
 type TransactionRepository interface {
 	Create(ctx context.Context, tx *domain.InventoryTransaction) error
 	GetByItemID(ctx context.Context, itemID uint) ([]domain.InventoryTransaction, error)
 	List(ctx context.Context) ([]domain.InventoryTransaction, error)
+	// StreamFiltered calls fn for every ledger entry in order, filtered by timestamp and item category.
+	StreamFiltered(ctx context.Context, filter domain.ExportFilter, fn func(domain.InventoryTransaction) error) error
+	// SumOutflows totals the stock removed per item in [from, to), ignoring the given reasons.
+	SumOutflows(ctx context.Context, from, to time.Time, excludeReasons []string) (map[uint]int, error)
 }
 
 type RequestRepository interface {
@@ -39,6 +53,66 @@ type IndentRepository interface {
 	Create(ctx context.Context, indent *domain.Indent) error
 	UpdateStatus(ctx context.Context, id uint, status string) error
 	Update(ctx context.Context, indent *domain.Indent) error
+	UpdateLine(ctx context.Context, line *domain.IndentLine) error
+	// List and GetByID load the indents' lines.
 	List(ctx context.Context) ([]domain.Indent, error)
 	GetByID(ctx context.Context, id uint) (*domain.Indent, error)
+	AddStatusChange(ctx context.Context, change *domain.IndentStatusChange) error
+	ListStatusChanges(ctx context.Context, indentID uint) ([]domain.IndentStatusChange, error)
+	AddReservation(ctx context.Context, reservation *domain.IndentReservation) error
+	// ListReservations returns the line's reservations with their batches.
+	ListReservations(ctx context.Context, lineID uint) ([]domain.IndentReservation, error)
+	DeleteReservations(ctx context.Context, lineID uint) error
+}
+
+type SupplyOrderRepository interface {
+	Create(ctx context.Context, order *domain.SupplyOrder) error
+	GetByID(ctx context.Context, id uint) (*domain.SupplyOrder, error)
+	List(ctx context.Context) ([]domain.SupplyOrder, error)
+	Update(ctx context.Context, order *domain.SupplyOrder) error
+	UpdateLine(ctx context.Context, line *domain.SupplyOrderLine) error
+	AddStatusChange(ctx context.Context, change *domain.SupplyOrderStatusChange) error
+	ListStatusChanges(ctx context.Context, orderID uint) ([]domain.SupplyOrderStatusChange, error)
+	// OutstandingByItem totals ordered but unreceived quantities per item, over orders in the given statuses.
+	OutstandingByItem(ctx context.Context, statuses []string) (map[uint]int, error)
+}
+
+type GoodsReceiptRepository interface {
+	// Create saves the receipt with its lines and assigns the GRN number.
+	Create(ctx context.Context, receipt *domain.GoodsReceipt) error
+	ListByOrder(ctx context.Context, orderID uint) ([]domain.GoodsReceipt, error)
+}
+
+// TxRepositories are repositories bound to a single database transaction.
+type TxRepositories struct {
+	Items         ItemRepository
+	Batches       BatchRepository
+	Transactions  TransactionRepository
+	Indents       IndentRepository
+	SupplyOrders  SupplyOrderRepository
+	GoodsReceipts GoodsReceiptRepository
+}
+
+// UnitOfWork runs fn inside one database transaction. Returning an error from fn rolls everything back.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos TxRepositories) error) error
+}
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetByID(ctx context.Context, id uint) (*domain.Category, error)
+	GetByName(ctx context.Context, name string) (*domain.Category, error)
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context) ([]domain.Category, error)
+	CountItems(ctx context.Context, id uint) (int64, error)
+}
+
+type SupplierRepository interface {
+	Create(ctx context.Context, supplier *domain.Supplier) error
+	GetByID(ctx context.Context, id uint) (*domain.Supplier, error)
+	GetByName(ctx context.Context, name string) (*domain.Supplier, error)
+	Update(ctx context.Context, supplier *domain.Supplier) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context) ([]domain.Supplier, error)
 }
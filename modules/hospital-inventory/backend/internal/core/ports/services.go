@@ -12,7 +12,7 @@ type InventoryService interface {
 	DeleteItem(ctx context.Context, id uint) error
 	ListItems(ctx context.Context) ([]domain.Item, error)
 	AddBatch(ctx context.Context, batch *domain.Batch, userID string) error
-	UpdateBatch(ctx context.Context, batch *domain.Batch, reason string, userID string) error
+	UpdateBatch(ctx context.Context, id uint, update domain.BatchUpdate, reason string, userID string) (*domain.Batch, error)
 	DeleteBatch(ctx context.Context, batchID uint, userID string) error
 	ListTransactions(ctx context.Context) ([]domain.InventoryTransaction, error)
 	GetDashboardStats(ctx context.Context) (*domain.DashboardStats, error)
@@ -28,8 +28,71 @@ type RequestService interface {
 }
 
 type IndentService interface {
-	CreateIndent(ctx context.Context, indent *domain.Indent) error
+	// CreateIndent accepts either lines or, from single-item clients, a bare item name and quantity.
+	CreateIndent(ctx context.Context, indent *domain.Indent, userID string) error
 	ListIndents(ctx context.Context) ([]domain.Indent, error)
 	GetIndent(ctx context.Context, id uint) (*domain.Indent, error)
-	ProcessIndent(ctx context.Context, indentID uint, status string) error
+	// ProcessIndent moves every line of the indent that can make the move to status, and records the
+	// transitions. Unknown statuses wrap domain.ErrUnknownIndentStatus; when no line may move the
+	// result is an *domain.IndentTransitionError. Lines dispatched short end PARTIALLY_DISPATCHED
+	// and their shortfall is raised as a backorder indent.
+	ProcessIndent(ctx context.Context, indentID uint, status, userID, note string) (*domain.Indent, error)
+	// ProcessIndentLine is ProcessIndent for a single line.
+	ProcessIndentLine(ctx context.Context, indentID, lineID uint, status, userID, note string) (*domain.Indent, error)
+	StatusHistory(ctx context.Context, indentID uint) ([]domain.IndentStatusChange, error)
+	PickList(ctx context.Context, indentID, lineID uint) (*domain.PickList, error)
+	// UpdatePickList replaces the batches reserved for a PROCESSING line. Dispatch deducts exactly these.
+	UpdatePickList(ctx context.Context, indentID, lineID uint, picks []domain.IndentPick, userID string) (*domain.Indent, error)
+}
+
+type ImportService interface {
+	// ImportItems validates a table of items and batches (header row first) and, unless dryRun is set
+	// or a row is invalid, writes it all in a single transaction.
+	ImportItems(ctx context.Context, rows [][]string, dryRun bool, userID string) (*domain.ImportReport, error)
+}
+
+type ExportService interface {
+	ExportStock(ctx context.Context, filter domain.ExportFilter, fn func(domain.StockRow) error) error
+	ExportTransactions(ctx context.Context, filter domain.ExportFilter, fn func(domain.InventoryTransaction) error) error
+}
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	GetCategory(ctx context.Context, id uint) (*domain.Category, error)
+	UpdateCategory(ctx context.Context, category *domain.Category) error
+	DeleteCategory(ctx context.Context, id uint) error
+	ListCategories(ctx context.Context) ([]domain.Category, error)
+}
+
+type SupplierService interface {
+	CreateSupplier(ctx context.Context, supplier *domain.Supplier) error
+	GetSupplier(ctx context.Context, id uint) (*domain.Supplier, error)
+	// FindSupplier resolves a supplier by ID, or by name when no ID is given.
+	FindSupplier(ctx context.Context, id *uint, name string) (*domain.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *domain.Supplier) error
+	DeleteSupplier(ctx context.Context, id uint) error
+	ListSuppliers(ctx context.Context) ([]domain.Supplier, error)
+}
+
+type SupplyOrderService interface {
+	// CreateOrder resolves the supplier (by SupplierID, or SupplierName when no ID is set),
+	// checks every line's item and computes the totals. New orders start as Draft.
+	CreateOrder(ctx context.Context, order *domain.SupplyOrder, userID string) error
+	GetOrder(ctx context.Context, id uint) (*domain.SupplyOrder, error)
+	ListOrders(ctx context.Context) ([]domain.SupplyOrder, error)
+	// ChangeStatus moves the order along its lifecycle and records the transition.
+	// Illegal moves fail with domain.ErrConflict.
+	ChangeStatus(ctx context.Context, id uint, status, userID, note string) (*domain.SupplyOrder, error)
+	StatusHistory(ctx context.Context, id uint) ([]domain.SupplyOrderStatusChange, error)
+}
+
+type ReorderService interface {
+	// SuggestReorders lists items at or below their reorder point, grouped by supplier.
+	SuggestReorders(ctx context.Context, params domain.ReorderParams) (*domain.ReorderReport, error)
+}
+
+type GoodsReceiptService interface {
+	// ReceiveOrder books a delivery into stock and moves the order to Partially Received or Received.
+	ReceiveOrder(ctx context.Context, orderID uint, receipt *domain.GoodsReceipt) error
+	ListReceipts(ctx context.Context, orderID uint) ([]domain.GoodsReceipt, error)
 }
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"strings"
+)
+
+type CategoryService struct {
+	repo ports.CategoryRepository
+}
+
+func NewCategoryService(repo ports.CategoryRepository) ports.CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
+	if err := s.validate(ctx, category); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, category)
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id uint) (*domain.Category, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	existing, err := s.repo.GetByID(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(ctx, category); err != nil {
+		return err
+	}
+	category.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, category)
+}
+
+// DeleteCategory refuses to orphan items; move them to another category first.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id uint) error {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	count, err := s.repo.CountItems(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: category is used by %d items", domain.ErrConflict, count)
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *CategoryService) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	return s.repo.List(ctx)
+}
+
+// validate trims the name and checks it is present and not taken by another category.
+func (s *CategoryService) validate(ctx context.Context, category *domain.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("%w: category name is required", domain.ErrValidation)
+	}
+	if category.MinShelfLifeDays < 0 {
+		return fmt.Errorf("%w: minimum shelf life cannot be negative", domain.ErrValidation)
+	}
+
+	existing, err := s.repo.GetByName(ctx, category.Name)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	if err == nil && existing.ID != category.ID {
+		return fmt.Errorf("%w: category %q already exists", domain.ErrConflict, existing.Name)
+	}
+	return nil
+}
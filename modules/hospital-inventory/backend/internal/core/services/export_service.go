@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"math"
+)
+
+type ExportService struct {
+	batchRepo ports.BatchRepository
+	txRepo    ports.TransactionRepository
+}
+
+func NewExportService(batchRepo ports.BatchRepository, txRepo ports.TransactionRepository) ports.ExportService {
+	return &ExportService{
+		batchRepo: batchRepo,
+		txRepo:    txRepo,
+	}
+}
+
+func (s *ExportService) ExportStock(ctx context.Context, filter domain.ExportFilter, fn func(domain.StockRow) error) error {
+	return s.batchRepo.StreamStock(ctx, filter, func(row domain.StockRow) error {
+		// Stock value follows the dashboard: quantity at MRP
+		if row.MRP != nil {
+			row.Value = math.Round(float64(row.Quantity)*(*row.MRP)*100) / 100
+		}
+		return fn(row)
+	})
+}
+
+func (s *ExportService) ExportTransactions(ctx context.Context, filter domain.ExportFilter, fn func(domain.InventoryTransaction) error) error {
+	return s.txRepo.StreamFiltered(ctx, filter, fn)
+}
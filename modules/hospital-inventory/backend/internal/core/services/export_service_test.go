@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestExportStock(t *testing.T) {
+	db := newTestDB(t)
+	drugs := &domain.Category{Name: "Drugs"}
+	surgical := &domain.Category{Name: "Surgical"}
+	mustCreate(t, db, drugs, surgical)
+	paracetamol := &domain.Item{Name: "Paracetamol", CategoryID: &drugs.ID, Unit: "Tablets"}
+	gauze := &domain.Item{Name: "Gauze", CategoryID: &surgical.ID, Unit: "Rolls"}
+	mustCreate(t, db, paracetamol, gauze)
+	mrp := 2.25
+	mustCreate(t, db,
+		&domain.Batch{ItemID: paracetamol.ID, BatchNumber: "P-LATE", Quantity: 10, MRP: &mrp, ExpiryDate: day("2027-03-01")},
+		&domain.Batch{ItemID: paracetamol.ID, BatchNumber: "P-SOON", Quantity: 3, MRP: &mrp, ExpiryDate: day("2026-01-15")},
+		&domain.Batch{ItemID: paracetamol.ID, BatchNumber: "P-EMPTY", Quantity: 0, ExpiryDate: day("2026-06-01")},
+		&domain.Batch{ItemID: gauze.ID, BatchNumber: "G-1", Quantity: 7, ExpiryDate: day("2026-06-30")},
+	)
+
+	service := NewExportService(repositories.NewGormBatchRepository(db), repositories.NewGormTransactionRepository(db))
+	tests := []struct {
+		name   string
+		filter domain.ExportFilter
+		want   []string
+	}{
+		{"everything in stock", domain.ExportFilter{}, []string{"G-1", "P-SOON", "P-LATE"}},
+		{"by category", domain.ExportFilter{CategoryID: &drugs.ID}, []string{"P-SOON", "P-LATE"}},
+		{"expiring in a range", domain.ExportFilter{From: day("2026-01-15"), To: day("2026-07-01")}, []string{"G-1", "P-SOON"}},
+		{"range end is exclusive", domain.ExportFilter{To: day("2026-06-30")}, []string{"P-SOON"}},
+		{"category and range", domain.ExportFilter{CategoryID: &surgical.ID, From: day("2027-01-01")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := service.ExportStock(context.Background(), tt.filter, func(row domain.StockRow) error {
+				got = append(got, row.BatchNumber)
+				if row.BatchNumber == "P-SOON" && (row.Category != "Drugs" || row.Unit != "Tablets" || row.Value != 6.75) {
+					t.Errorf("P-SOON row = %+v, want Drugs, Tablets and a value of 6.75", row)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The ledger is streamed in pages of 500; a filtered export must still see every row once, in order.
+func TestExportTransactions(t *testing.T) {
+	db := newTestDB(t)
+	drugs := &domain.Category{Name: "Drugs"}
+	surgical := &domain.Category{Name: "Surgical"}
+	mustCreate(t, db, drugs, surgical)
+	paracetamol := &domain.Item{Name: "Paracetamol", CategoryID: &drugs.ID}
+	gauze := &domain.Item{Name: "Gauze", CategoryID: &surgical.ID}
+	mustCreate(t, db, paracetamol, gauze)
+
+	// Two entries a day from 1 January 2026, alternating between the items
+	start := day("2026-01-01")
+	var ledger []domain.InventoryTransaction
+	for i := 0; i < 1300; i++ {
+		item := paracetamol
+		if i%2 == 1 {
+			item = gauze
+		}
+		ledger = append(ledger, domain.InventoryTransaction{
+			ItemID: item.ID, QuantityChange: -1, Reason: "Indent",
+			Timestamp: start.AddDate(0, 0, i/2).Add(time.Duration(9+i%2) * time.Hour),
+		})
+	}
+	if err := db.CreateInBatches(ledger, 200).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Soft-deleted items keep their history in the export
+	if err := db.Delete(gauze).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewExportService(repositories.NewGormBatchRepository(db), repositories.NewGormTransactionRepository(db))
+	tests := []struct {
+		name      string
+		filter    domain.ExportFilter
+		want      int
+		wantFirst time.Time
+		itemName  string
+	}{
+		{"whole ledger", domain.ExportFilter{}, 1300, start.Add(9 * time.Hour), ""},
+		{"by category", domain.ExportFilter{CategoryID: &surgical.ID}, 650, start.Add(10 * time.Hour), "Gauze"},
+		{"date range", domain.ExportFilter{From: day("2026-02-01"), To: day("2026-03-01")}, 56, day("2026-02-01").Add(9 * time.Hour), ""},
+		{"category and date range", domain.ExportFilter{CategoryID: &drugs.ID, From: day("2026-02-01"), To: day("2026-03-01")}, 28, day("2026-02-01").Add(9 * time.Hour), "Paracetamol"},
+		{"nothing in range", domain.ExportFilter{From: day("2030-01-01")}, 0, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []domain.InventoryTransaction
+			err := service.ExportTransactions(context.Background(), tt.filter, func(tx domain.InventoryTransaction) error {
+				got = append(got, tx)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("exported %d entries, want %d", len(got), tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			if !got[0].Timestamp.Equal(tt.wantFirst) {
+				t.Errorf("first entry at %v, want %v", got[0].Timestamp, tt.wantFirst)
+			}
+			for i := range got {
+				if i > 0 && got[i].ID <= got[i-1].ID {
+					t.Fatalf("entry %d has id %d after %d, want ascending ids", i, got[i].ID, got[i-1].ID)
+				}
+				if tt.itemName != "" && got[i].Item.Name != tt.itemName {
+					t.Fatalf("entry %d is for %q, want %q", i, got[i].Item.Name, tt.itemName)
+				}
+			}
+		})
+	}
+}
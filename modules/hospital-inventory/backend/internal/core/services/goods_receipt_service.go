@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"strings"
+	"time"
+)
+
+type GoodsReceiptService struct {
+	receiptRepo ports.GoodsReceiptRepository
+	uow         ports.UnitOfWork
+}
+
+func NewGoodsReceiptService(receiptRepo ports.GoodsReceiptRepository, uow ports.UnitOfWork) ports.GoodsReceiptService {
+	return &GoodsReceiptService{
+		receiptRepo: receiptRepo,
+		uow:         uow,
+	}
+}
+
+func (s *GoodsReceiptService) ListReceipts(ctx context.Context, orderID uint) ([]domain.GoodsReceipt, error) {
+	return s.receiptRepo.ListByOrder(ctx, orderID)
+}
+
+func (s *GoodsReceiptService) ReceiveOrder(ctx context.Context, orderID uint, receipt *domain.GoodsReceipt) error {
+	if len(receipt.Lines) == 0 {
+		return fmt.Errorf("%w: a goods receipt needs at least one line", domain.ErrValidation)
+	}
+	if receipt.ReceivedAt.IsZero() {
+		receipt.ReceivedAt = time.Now()
+	}
+	receipt.SupplyOrderID = orderID
+
+	return s.uow.Do(ctx, func(repos ports.TxRepositories) error {
+		order, err := repos.SupplyOrders.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if !order.IsReceivable() {
+			return fmt.Errorf("%w: order %s is %s and cannot be received", domain.ErrConflict, order.PONumber(), order.Status)
+		}
+
+		for i := range receipt.Lines {
+			line := &receipt.Lines[i]
+			item, err := repos.Items.GetByID(ctx, line.ItemID)
+			if err != nil {
+				return fmt.Errorf("%w: line %d: item %d does not exist", domain.ErrValidation, i+1, line.ItemID)
+			}
+			if err := validateReceiptLine(line); err != nil {
+				return fmt.Errorf("%w: line %d (%s): %v", domain.ErrValidation, i+1, item.Name, err)
+			}
+
+			if err := allocateReceived(order, item, line.Quantity); err != nil {
+				return fmt.Errorf("%w: line %d: %v", domain.ErrValidation, i+1, err)
+			}
+
+			batch, err := receiveIntoBatch(ctx, repos.Batches, order, line)
+			if err != nil {
+				return fmt.Errorf("line %d (%s): %w", i+1, item.Name, err)
+			}
+			line.BatchID = batch.ID
+
+			if err := repos.Transactions.Create(ctx, &domain.InventoryTransaction{
+				ItemID:         item.ID,
+				BatchID:        &batch.ID,
+				QuantityChange: line.Quantity,
+				Reason:         "Purchase",
+				ReferenceID:    order.PONumber(),
+				PerformedBy:    receipt.ReceivedBy,
+				Timestamp:      receipt.ReceivedAt,
+				Notes:          fmt.Sprintf("Received batch %s from %s", line.BatchNumber, order.SupplierName),
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.GoodsReceipts.Create(ctx, receipt); err != nil {
+			return err
+		}
+
+		// Lines whose item could not be resolved by the migration can never be received
+		status := domain.OrderStatusReceived
+		for i := range order.Lines {
+			ol := &order.Lines[i]
+			if err := repos.SupplyOrders.UpdateLine(ctx, ol); err != nil {
+				return err
+			}
+			if ol.ItemID != 0 && ol.ReceivedQuantity < ol.Quantity {
+				status = domain.OrderStatusPartiallyReceived
+			}
+		}
+		return transitionOrder(ctx, repos.SupplyOrders, order, status, receipt.ReceivedBy, "Goods receipt "+receipt.ReceiptNumber)
+	})
+}
+
+func validateReceiptLine(line *domain.GoodsReceiptLine) error {
+	line.BatchNumber = strings.TrimSpace(line.BatchNumber)
+	switch {
+	case line.BatchNumber == "":
+		return fmt.Errorf("batch number is required")
+	case line.Quantity <= 0:
+		return fmt.Errorf("received quantity must be positive")
+	case line.ExpiryDate.IsZero():
+		return fmt.Errorf("expiry date is required")
+	case line.ExpiryDate.Before(time.Now()):
+		return fmt.Errorf("batch %s expired on %s", line.BatchNumber, line.ExpiryDate.Format("2006-01-02"))
+	case line.PurchasePrice < 0:
+		return fmt.Errorf("purchase price cannot be negative")
+	case line.MRP != nil && *line.MRP < 0:
+		return fmt.Errorf("MRP cannot be negative")
+	}
+	return nil
+}
+
+// allocateReceived books qty against the order's open lines for the item, in line order.
+func allocateReceived(order *domain.SupplyOrder, item *domain.Item, qty int) error {
+	open := 0
+	onOrder := false
+	for _, ol := range order.Lines {
+		if ol.ItemID == item.ID {
+			onOrder = true
+			open += ol.Quantity - ol.ReceivedQuantity
+		}
+	}
+	if !onOrder {
+		return fmt.Errorf("%s is not on order %d", item.Name, order.ID)
+	}
+	if qty > open {
+		return fmt.Errorf("receiving %d %s would exceed the %d still outstanding", qty, item.Name, open)
+	}
+
+	for i := range order.Lines {
+		ol := &order.Lines[i]
+		if ol.ItemID != item.ID || qty == 0 {
+			continue
+		}
+		take := ol.Quantity - ol.ReceivedQuantity
+		if take > qty {
+			take = qty
+		}
+		ol.ReceivedQuantity += take
+		qty -= take
+	}
+	return nil
+}
+
+// receiveIntoBatch books received stock. A repeat delivery of the same batch tops up the existing
+// batch, as long as the expiry agrees; otherwise a new batch is created.
+func receiveIntoBatch(ctx context.Context, batches ports.BatchRepository, order *domain.SupplyOrder, line *domain.GoodsReceiptLine) (*domain.Batch, error) {
+	existing, err := batches.GetByItemID(ctx, line.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range existing {
+		b := &existing[i]
+		if !strings.EqualFold(b.BatchNumber, line.BatchNumber) {
+			continue
+		}
+		if !b.ExpiryDate.Equal(line.ExpiryDate) {
+			return nil, fmt.Errorf("%w: batch %s is already in stock with expiry %s", domain.ErrValidation,
+				b.BatchNumber, b.ExpiryDate.Format("2006-01-02"))
+		}
+		b.Quantity += line.Quantity
+		if line.PurchasePrice > 0 {
+			b.PurchasePrice = line.PurchasePrice
+		}
+		if line.MRP != nil {
+			b.MRP = line.MRP
+		}
+		return b, batches.Update(ctx, b)
+	}
+
+	batch := &domain.Batch{
+		ItemID:        line.ItemID,
+		BatchNumber:   line.BatchNumber,
+		Quantity:      line.Quantity,
+		MRP:           line.MRP,
+		PurchasePrice: line.PurchasePrice,
+		ExpiryDate:    line.ExpiryDate,
+		Location:      line.Location,
+		SupplierID:    order.SupplierID,
+	}
+	return batch, batches.Create(ctx, batch)
+}
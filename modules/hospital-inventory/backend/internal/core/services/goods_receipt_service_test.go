@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
+	"testing"
+	"time"
+)
+
+// Receipts are booked in the ledger under the number printed on the purchase order.
+func TestReceiveOrderLedgerReference(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	item := &domain.Item{Name: "Gauze Roll", Unit: "Rolls"}
+	mustCreate(t, db, item)
+	order := &domain.SupplyOrder{
+		Status:    domain.OrderStatusSent,
+		OrderDate: time.Now(),
+		Lines:     []domain.SupplyOrderLine{{ItemID: item.ID, ItemName: item.Name, Quantity: 20, UnitCost: 12}},
+	}
+	mustCreate(t, db, order)
+
+	service := NewGoodsReceiptService(repositories.NewGormGoodsReceiptRepository(db), repositories.NewGormUnitOfWork(db))
+	receipt := &domain.GoodsReceipt{Lines: []domain.GoodsReceiptLine{{
+		ItemID: item.ID, BatchNumber: "GZ-1", Quantity: 20, ExpiryDate: time.Now().AddDate(2, 0, 0),
+	}}}
+	if err := service.ReceiveOrder(ctx, order.ID, receipt); err != nil {
+		t.Fatal(err)
+	}
+
+	var movements []domain.InventoryTransaction
+	if err := db.Where("reason = ?", "Purchase").Find(&movements).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(movements) != 1 || movements[0].ReferenceID != order.PONumber() {
+		t.Fatalf("purchase movements = %+v, want one referencing %s", movements, order.PONumber())
+	}
+	if movements[0].ReferenceID != "PO-000001" {
+		t.Errorf("reference = %q, want PO-000001", movements[0].ReferenceID)
+	}
+}
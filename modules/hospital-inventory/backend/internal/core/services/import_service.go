@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importColumns maps accepted header spellings to the canonical column name.
+var importColumns = map[string]string{
+	"name": "name", "item": "name", "item_name": "name",
+	"unit":      "unit",
+	"threshold": "threshold", "min_stock": "threshold",
+	"batch_number": "batch_number", "batch": "batch_number", "batch_no": "batch_number",
+	"expiry": "expiry_date", "expiry_date": "expiry_date", "exp_date": "expiry_date",
+	"quantity": "quantity", "qty": "quantity",
+	"mrp":      "mrp",
+	"location": "location", "rack": "location",
+}
+
+// importDateLayouts are tried in order; slashed and dashed day-first dates follow Indian usage.
+var importDateLayouts = []string{"2006-01-02", "02/01/2006", "02-01-2006", "2006/01/02", time.RFC3339}
+
+// excelEpoch is day zero for spreadsheet serial dates.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type importRow struct {
+	row         int
+	name        string
+	unit        string
+	threshold   *int // nil when the row leaves it blank
+	batchNumber string
+	expiry      time.Time
+	quantity    int
+	mrp         *float64
+	location    string
+}
+
+type ImportService struct {
+	itemRepo  ports.ItemRepository
+	batchRepo ports.BatchRepository
+	uow       ports.UnitOfWork
+}
+
+func NewImportService(itemRepo ports.ItemRepository, batchRepo ports.BatchRepository, uow ports.UnitOfWork) ports.ImportService {
+	return &ImportService{
+		itemRepo:  itemRepo,
+		batchRepo: batchRepo,
+		uow:       uow,
+	}
+}
+
+func (s *ImportService) ImportItems(ctx context.Context, table [][]string, dryRun bool, userID string) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{DryRun: dryRun, Errors: []domain.ImportRowError{}, Warnings: []domain.ImportRowError{}}
+
+	rows, errs := parseImportTable(table)
+	report.TotalRows = len(rows) + countInvalidRows(errs)
+	report.Errors = append(report.Errors, errs...)
+
+	// Match against every known item, not just hospital stock, since the items table is shared with the pharmacy
+	known, err := s.itemRepo.GetKnowledgeBase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*domain.Item, len(known))
+	for i := range known {
+		existing[strings.ToLower(known[i].Name)] = &known[i]
+	}
+
+	newItems := make(map[string]*domain.Item)
+	seenBatches := make(map[string]int)
+	existingBatches := make(map[uint]map[string]bool)
+	for _, r := range rows {
+		key := strings.ToLower(r.name)
+		item, found := existing[key]
+		if !found {
+			if newItems[key] == nil {
+				newItems[key] = r.newItem()
+			} else {
+				report.Warnings = append(report.Warnings, ignoredItemColumns(r, newItems[key])...)
+			}
+		} else {
+			report.Warnings = append(report.Warnings, ignoredItemColumns(r, item)...)
+		}
+		if r.batchNumber == "" {
+			continue
+		}
+
+		batchKey := key + "\x00" + strings.ToLower(r.batchNumber)
+		if first, dup := seenBatches[batchKey]; dup {
+			report.Errors = append(report.Errors, domain.ImportRowError{
+				Row: r.row, Field: "batch_number",
+				Message: fmt.Sprintf("batch %s for %s is repeated, first seen on row %d", r.batchNumber, r.name, first),
+			})
+			continue
+		}
+		seenBatches[batchKey] = r.row
+
+		if found {
+			batchNumbers, ok := existingBatches[item.ID]
+			if !ok {
+				batches, err := s.batchRepo.GetByItemID(ctx, item.ID)
+				if err != nil {
+					return nil, err
+				}
+				batchNumbers = make(map[string]bool, len(batches))
+				for _, b := range batches {
+					batchNumbers[strings.ToLower(b.BatchNumber)] = true
+				}
+				existingBatches[item.ID] = batchNumbers
+			}
+			if batchNumbers[strings.ToLower(r.batchNumber)] {
+				report.Errors = append(report.Errors, domain.ImportRowError{
+					Row: r.row, Field: "batch_number",
+					Message: fmt.Sprintf("batch %s already exists for %s", r.batchNumber, item.Name),
+				})
+				continue
+			}
+		}
+		report.BatchesCreated++
+	}
+	report.ItemsCreated = len(newItems)
+
+	if dryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	reference := fmt.Sprintf("IMPORT-%s", time.Now().Format("20060102150405"))
+	err = s.uow.Do(ctx, func(repos ports.TxRepositories) error {
+		created := make(map[string]*domain.Item)
+		for _, r := range rows {
+			key := strings.ToLower(r.name)
+			item, found := existing[key]
+			notes := "Imported item"
+			if !found {
+				if item, found = created[key]; !found {
+					item = r.newItem()
+					if err := repos.Items.Create(ctx, item); err != nil {
+						return fmt.Errorf("row %d: %w", r.row, err)
+					}
+					created[key] = item
+					notes = "Item created by import"
+				}
+			}
+
+			tx := &domain.InventoryTransaction{
+				ItemID:      item.ID,
+				Reason:      "Import",
+				ReferenceID: reference,
+				PerformedBy: userID,
+				Timestamp:   time.Now(),
+				Notes:       fmt.Sprintf("%s (row %d)", notes, r.row),
+			}
+
+			if r.batchNumber != "" {
+				batch := &domain.Batch{
+					ItemID:      item.ID,
+					BatchNumber: r.batchNumber,
+					Quantity:    r.quantity,
+					ExpiryDate:  r.expiry,
+					Location:    r.location,
+					MRP:         r.mrp,
+				}
+				if err := repos.Batches.Create(ctx, batch); err != nil {
+					return fmt.Errorf("row %d: %w", r.row, err)
+				}
+				tx.BatchID = &batch.ID
+				tx.QuantityChange = batch.Quantity
+				tx.Notes = fmt.Sprintf("Imported batch %s (row %d)", batch.BatchNumber, r.row)
+			}
+
+			if err := repos.Transactions.Create(ctx, tx); err != nil {
+				return fmt.Errorf("row %d: %w", r.row, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Committed = true
+	return report, nil
+}
+
+// parseImportTable maps the header row and validates every data row on its own.
+// Rows with errors are left out of the result.
+func parseImportTable(table [][]string) ([]importRow, []domain.ImportRowError) {
+	var errs []domain.ImportRowError
+	// The header is the first row with anything in it; blank rows above it keep their numbers
+	header := 0
+	for header < len(table) && isBlankRecord(table[header]) {
+		header++
+	}
+	if header == len(table) {
+		return nil, []domain.ImportRowError{{Row: 1, Message: "file is empty"}}
+	}
+
+	columns := make(map[string]int)
+	for i, title := range table[header] {
+		key := strings.ToLower(strings.TrimSpace(title))
+		key = strings.NewReplacer(" ", "_", "-", "_", ".", "").Replace(key)
+		if name, ok := importColumns[key]; ok {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, []domain.ImportRowError{{Row: header + 1, Field: "name", Message: "header row must include a name column"}}
+	}
+
+	var rows []importRow
+	for i, record := range table[header+1:] {
+		rowNum := header + i + 2
+		get := func(column string) string {
+			idx, ok := columns[column]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		r := importRow{
+			row:         rowNum,
+			name:        get("name"),
+			unit:        get("unit"),
+			batchNumber: get("batch_number"),
+			location:    get("location"),
+		}
+		rowErr := func(field, format string, args ...interface{}) {
+			errs = append(errs, domain.ImportRowError{Row: rowNum, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+		before := len(errs)
+
+		if r.name == "" {
+			rowErr("name", "name is required")
+		}
+		if v := get("threshold"); v != "" {
+			n, err := parseWholeNumber(v)
+			if err != nil || n < 0 {
+				rowErr("threshold", "threshold must be a whole number of 0 or more, got %q", v)
+			}
+			r.threshold = &n
+		}
+
+		if r.batchNumber != "" {
+			if v := get("quantity"); v == "" {
+				rowErr("quantity", "quantity is required for a batch")
+			} else if n, err := parseWholeNumber(v); err != nil || n < 0 {
+				rowErr("quantity", "quantity must be a whole number of 0 or more, got %q", v)
+			} else {
+				r.quantity = n
+			}
+
+			if v := get("expiry_date"); v == "" {
+				rowErr("expiry_date", "expiry date is required for a batch")
+			} else if expiry, ok := parseImportDate(v); !ok {
+				rowErr("expiry_date", "unrecognised expiry date %q, use YYYY-MM-DD", v)
+			} else {
+				r.expiry = expiry
+			}
+
+			if v := get("mrp"); v != "" {
+				mrp, err := strconv.ParseFloat(v, 64)
+				if err != nil || mrp < 0 {
+					rowErr("mrp", "mrp must be a number of 0 or more, got %q", v)
+				}
+				r.mrp = &mrp
+			}
+		} else {
+			for _, column := range []string{"quantity", "expiry_date", "mrp", "location"} {
+				if get(column) != "" {
+					rowErr("batch_number", "batch number is required when %s is given", column)
+					break
+				}
+			}
+		}
+
+		if len(errs) == before {
+			rows = append(rows, r)
+		}
+	}
+	return rows, errs
+}
+
+// newItem is the item a row creates when its name is not known yet.
+func (r importRow) newItem() *domain.Item {
+	item := &domain.Item{Name: r.name, Unit: r.unit}
+	if r.threshold != nil {
+		item.Threshold = *r.threshold
+	}
+	return item
+}
+
+// ignoredItemColumns warns about unit and threshold values that differ from the item the row
+// adds to. An import only sets them on the items it creates, from the item's first row.
+func ignoredItemColumns(r importRow, item *domain.Item) []domain.ImportRowError {
+	var warnings []domain.ImportRowError
+	if r.unit != "" && !strings.EqualFold(r.unit, item.Unit) {
+		warnings = append(warnings, domain.ImportRowError{
+			Row: r.row, Field: "unit",
+			Message: fmt.Sprintf("unit %q ignored, %s keeps its unit %q", r.unit, item.Name, item.Unit),
+		})
+	}
+	if r.threshold != nil && *r.threshold != item.Threshold {
+		warnings = append(warnings, domain.ImportRowError{
+			Row: r.row, Field: "threshold",
+			Message: fmt.Sprintf("threshold %d ignored, %s keeps its threshold %d", *r.threshold, item.Name, item.Threshold),
+		})
+	}
+	return warnings
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// countInvalidRows counts distinct data rows that failed validation.
+func countInvalidRows(errs []domain.ImportRowError) int {
+	rows := make(map[int]bool)
+	for _, e := range errs {
+		if e.Row > 1 {
+			rows[e.Row] = true
+		}
+	}
+	return len(rows)
+}
+
+// parseWholeNumber accepts "12" and also "12.0", which is how spreadsheets store integers.
+func parseWholeNumber(v string) (int, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%q is not a whole number", v)
+	}
+	return int(f), nil
+}
+
+func parseImportDate(v string) (time.Time, bool) {
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	// XLSX date cells arrive as serial day numbers
+	if serial, err := strconv.ParseFloat(v, 64); err == nil && serial > 0 && serial < 2958466 {
+		return excelEpoch.AddDate(0, 0, int(serial)), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseImportTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    [][]string
+		wantRows []int // spreadsheet row numbers of the valid rows
+		wantErrs []domain.ImportRowError
+	}{
+		{
+			name:     "header spellings",
+			table:    [][]string{{"Item Name", "Batch No", "Exp-Date", "Qty", "M.R.P", "Rack"}, {"Gauze", "G1", "2030-01-31", "5", "12.5", "A1"}},
+			wantRows: []int{2},
+		},
+		{
+			name:     "blank rows keep their numbers",
+			table:    [][]string{nil, {"", " "}, {"name"}, {"Gauze"}, nil, {"Syringe"}},
+			wantRows: []int{4, 6},
+		},
+		{
+			name:     "empty file",
+			table:    [][]string{nil, {""}},
+			wantErrs: []domain.ImportRowError{{Row: 1, Message: "file is empty"}},
+		},
+		{
+			name:     "no name column",
+			table:    [][]string{nil, {"unit", "qty"}, {"Box", "1"}},
+			wantErrs: []domain.ImportRowError{{Row: 2, Field: "name", Message: "header row must include a name column"}},
+		},
+		{
+			name:  "every problem of a row is reported",
+			table: [][]string{{"name", "threshold", "batch", "expiry", "qty", "mrp"}, {"", "-1", "B1", "31 Jan", "2.5", "x"}},
+			wantErrs: []domain.ImportRowError{
+				{Row: 2, Field: "name", Message: "name is required"},
+				{Row: 2, Field: "threshold", Message: `threshold must be a whole number of 0 or more, got "-1"`},
+				{Row: 2, Field: "quantity", Message: `quantity must be a whole number of 0 or more, got "2.5"`},
+				{Row: 2, Field: "expiry_date", Message: `unrecognised expiry date "31 Jan", use YYYY-MM-DD`},
+				{Row: 2, Field: "mrp", Message: `mrp must be a number of 0 or more, got "x"`},
+			},
+		},
+		{
+			name:     "batch columns need a batch number",
+			table:    [][]string{{"name", "batch", "qty", "location"}, {"Gauze", "", "", "Rack A"}, {"Gauze", "", "", ""}},
+			wantRows: []int{3},
+			wantErrs: []domain.ImportRowError{{Row: 2, Field: "batch_number", Message: "batch number is required when location is given"}},
+		},
+		{
+			name:     "batches need quantity and expiry",
+			table:    [][]string{{"name", "batch"}, {"Gauze", "G1"}},
+			wantErrs: []domain.ImportRowError{{Row: 2, Field: "quantity", Message: "quantity is required for a batch"}, {Row: 2, Field: "expiry_date", Message: "expiry date is required for a batch"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs := parseImportTable(tt.table)
+			var gotRows []int
+			for _, r := range rows {
+				gotRows = append(gotRows, r.row)
+			}
+			if !reflect.DeepEqual(gotRows, tt.wantRows) {
+				t.Errorf("valid rows = %v, want %v", gotRows, tt.wantRows)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("errors = %+v, want %+v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	want := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	for _, v := range []string{"2024-01-31", "31/01/2024", "31-01-2024", "2024/01/31", "45322", "45322.75"} {
+		got, ok := parseImportDate(v)
+		if !ok || !got.Equal(want) {
+			t.Errorf("parseImportDate(%q) = %v, %v, want %v", v, got, ok, want)
+		}
+	}
+	for _, v := range []string{"01/31/2024", "Jan 2024", "0", "-5", "3000000"} {
+		if got, ok := parseImportDate(v); ok {
+			t.Errorf("parseImportDate(%q) = %v, want it rejected", v, got)
+		}
+	}
+}
+
+func TestImportItems(t *testing.T) {
+	table := [][]string{
+		{"name", "unit", "threshold", "batch", "expiry", "qty", "mrp"},
+		{"Paracetamol 500mg", "Strips", "50", "P2", "2030-06-30", "40", "2.5"},
+		{"Gauze Roll", "Rolls", "5", "G1", "45322", "10", ""},
+		{"Gauze Roll", "Boxes", "", "G2", "2030-01-31", "3", ""},
+		{"Bandage", "", "", "", "", "", ""},
+	}
+
+	db := newTestDB(t)
+	ctx := context.Background()
+	existing := &domain.Item{Name: "Paracetamol 500mg", Unit: "Tablets", Threshold: 100}
+	mustCreate(t, db, existing)
+	mustCreate(t, db, &domain.Batch{ItemID: existing.ID, BatchNumber: "P1", Quantity: 10, ExpiryDate: time.Now().AddDate(1, 0, 0)})
+
+	service := NewImportService(repositories.NewGormItemRepository(db), repositories.NewGormBatchRepository(db), repositories.NewGormUnitOfWork(db))
+	wantWarnings := []domain.ImportRowError{
+		{Row: 2, Field: "unit", Message: `unit "Strips" ignored, Paracetamol 500mg keeps its unit "Tablets"`},
+		{Row: 2, Field: "threshold", Message: "threshold 50 ignored, Paracetamol 500mg keeps its threshold 100"},
+		{Row: 4, Field: "unit", Message: `unit "Boxes" ignored, Gauze Roll keeps its unit "Rolls"`},
+	}
+	counts := func() (items, batches, movements int64) {
+		db.Model(&domain.Item{}).Count(&items)
+		db.Model(&domain.Batch{}).Count(&batches)
+		db.Model(&domain.InventoryTransaction{}).Count(&movements)
+		return
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := service.ImportItems(ctx, table, true, "tester")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Committed || report.TotalRows != 4 || report.ItemsCreated != 2 || report.BatchesCreated != 3 || len(report.Errors) != 0 {
+			t.Errorf("report = %+v, want 4 rows, 2 items and 3 batches, not committed", report)
+		}
+		if !reflect.DeepEqual(report.Warnings, wantWarnings) {
+			t.Errorf("warnings = %+v, want %+v", report.Warnings, wantWarnings)
+		}
+		if items, batches, movements := counts(); items != 1 || batches != 1 || movements != 0 {
+			t.Errorf("dry run wrote %d items, %d batches, %d movements", items, batches, movements)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		report, err := service.ImportItems(ctx, table, false, "tester")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.Committed || report.ItemsCreated != 2 || report.BatchesCreated != 3 {
+			t.Fatalf("report = %+v, want it committed with 2 items and 3 batches", report)
+		}
+		if !reflect.DeepEqual(report.Warnings, wantWarnings) {
+			t.Errorf("warnings = %+v, want %+v", report.Warnings, wantWarnings)
+		}
+		if items, batches, movements := counts(); items != 3 || batches != 4 || movements != 4 {
+			t.Errorf("have %d items, %d batches, %d movements, want 3, 4 and 4", items, batches, movements)
+		}
+
+		var paracetamol, gauze domain.Item
+		db.First(&paracetamol, existing.ID)
+		db.Where("name = ?", "Gauze Roll").First(&gauze)
+		if paracetamol.Unit != "Tablets" || paracetamol.Threshold != 100 {
+			t.Errorf("existing item changed to unit %q threshold %d", paracetamol.Unit, paracetamol.Threshold)
+		}
+		if gauze.Unit != "Rolls" || gauze.Threshold != 5 {
+			t.Errorf("new item has unit %q threshold %d, want the first row's Rolls and 5", gauze.Unit, gauze.Threshold)
+		}
+
+		var g1 domain.Batch
+		db.Where("batch_number = ?", "G1").First(&g1)
+		if want := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC); !g1.ExpiryDate.Equal(want) {
+			t.Errorf("serial date expiry = %v, want %v", g1.ExpiryDate, want)
+		}
+	})
+
+	t.Run("importing the same file again", func(t *testing.T) {
+		report, err := service.ImportItems(ctx, table, false, "tester")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Committed || len(report.Errors) != 3 {
+			t.Errorf("report = %+v, want three repeated batch errors and nothing committed", report)
+		}
+		if items, batches, _ := counts(); items != 3 || batches != 4 {
+			t.Errorf("have %d items and %d batches after a rejected import, want 3 and 4", items, batches)
+		}
+	})
+}
@@ -3,36 +3,107 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/ports"
+	"strings"
 	"time"
 )
 
 type IndentService struct {
-	repo      ports.IndentRepository
-	itemRepo  ports.ItemRepository
-	batchRepo ports.BatchRepository
-	txRepo    ports.TransactionRepository
-}
-
-func NewIndentService(
-	repo ports.IndentRepository,
-	itemRepo ports.ItemRepository,
-	batchRepo ports.BatchRepository,
-	txRepo ports.TransactionRepository,
-) ports.IndentService {
+	repo       ports.IndentRepository
+	itemRepo   ports.ItemRepository
+	batchRepo  ports.BatchRepository
+	uow        ports.UnitOfWork
+	allocation domain.AllocationStrategy
+}
+
+// batchDetail is one entry of a line's dispatch_details, as read by the pharmacy.
+type batchDetail struct {
+	BatchNumber string    `json:"batch_number"`
+	Quantity    int       `json:"quantity"`
+	ExpiryDate  time.Time `json:"expiry_date"`
+	MRP         float64   `json:"mrp"`
+	Location    string    `json:"location"`
+}
+
+func newBatchDetail(b *domain.Batch, qty int) batchDetail {
+	mrp := 0.0
+	if b.MRP != nil {
+		mrp = *b.MRP
+	}
+	return batchDetail{
+		BatchNumber: b.BatchNumber,
+		Quantity:    qty,
+		ExpiryDate:  b.ExpiryDate,
+		MRP:         mrp,
+		Location:    b.Location,
+	}
+}
+
+// NewIndentService allocates stock with the given strategy, FEFO when it is nil.
+func NewIndentService(repo ports.IndentRepository, itemRepo ports.ItemRepository, batchRepo ports.BatchRepository, uow ports.UnitOfWork, allocation domain.AllocationStrategy) ports.IndentService {
+	if allocation == nil {
+		allocation = domain.FEFO{}
+	}
 	return &IndentService{
-		repo:      repo,
-		itemRepo:  itemRepo,
-		batchRepo: batchRepo,
-		txRepo:    txRepo,
+		repo:       repo,
+		itemRepo:   itemRepo,
+		batchRepo:  batchRepo,
+		uow:        uow,
+		allocation: allocation,
 	}
 }
 
-func (s *IndentService) CreateIndent(ctx context.Context, indent *domain.Indent) error {
-	indent.Status = "PENDING"
-	return s.repo.Create(ctx, indent)
+func (s *IndentService) CreateIndent(ctx context.Context, indent *domain.Indent, userID string) error {
+	// Single-item clients such as the pharmacy send the item on the indent itself
+	if len(indent.Lines) == 0 {
+		indent.Lines = []domain.IndentLine{{ItemName: indent.ItemName, Quantity: indent.Quantity}}
+	}
+
+	seen := make(map[string]int, len(indent.Lines))
+	for i := range indent.Lines {
+		l := &indent.Lines[i]
+		l.ItemName = strings.TrimSpace(l.ItemName)
+		if l.ItemName == "" {
+			return fmt.Errorf("%w: line %d: item name is required", domain.ErrValidation, i+1)
+		}
+		if l.Quantity <= 0 {
+			return fmt.Errorf("%w: line %d (%s): quantity must be positive", domain.ErrValidation, i+1, l.ItemName)
+		}
+		key := strings.ToLower(l.ItemName)
+		if first, dup := seen[key]; dup {
+			return fmt.Errorf("%w: line %d: %s is already on line %d", domain.ErrValidation, i+1, l.ItemName, first)
+		}
+		seen[key] = i + 1
+
+		l.ID = 0
+		l.Status = domain.IndentStatusPending
+		l.ApprovedQuantity = 0
+		l.DispatchedQuantity = 0
+		l.DispatchDetails = ""
+	}
+
+	indent.BackorderOfID = nil
+	indent.Summarize()
+	if err := s.repo.Create(ctx, indent); err != nil {
+		return err
+	}
+	return s.repo.AddStatusChange(ctx, &domain.IndentStatusChange{
+		IndentID:  indent.ID,
+		ToStatus:  indent.Status,
+		Actor:     userID,
+		Note:      fmt.Sprintf("Raised by pharmacy %s", indent.PharmacyID),
+		ChangedAt: time.Now(),
+	})
+}
+
+func (s *IndentService) StatusHistory(ctx context.Context, indentID uint) ([]domain.IndentStatusChange, error) {
+	if _, err := s.repo.GetByID(ctx, indentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusChanges(ctx, indentID)
 }
 
 func (s *IndentService) ListIndents(ctx context.Context) ([]domain.Indent, error) {
@@ -43,151 +114,508 @@ func (s *IndentService) GetIndent(ctx context.Context, id uint) (*domain.Indent,
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *IndentService) ProcessIndent(ctx context.Context, indentID uint, status string) error {
-	indent, err := s.repo.GetByID(ctx, indentID)
-	if err != nil {
-		return err
+func (s *IndentService) ProcessIndent(ctx context.Context, indentID uint, status, userID, note string) (*domain.Indent, error) {
+	return s.process(ctx, indentID, 0, status, userID, note)
+}
+
+func (s *IndentService) ProcessIndentLine(ctx context.Context, indentID, lineID uint, status, userID, note string) (*domain.Indent, error) {
+	if lineID == 0 {
+		return nil, fmt.Errorf("%w: indent %d has no line 0", domain.ErrNotFound, indentID)
 	}
+	return s.process(ctx, indentID, lineID, status, userID, note)
+}
 
-	// Helper struct for JSON details
-	type BatchDetail struct {
-		BatchNumber string    `json:"batch_number"`
-		Quantity    int       `json:"quantity"`
-		ExpiryDate  time.Time `json:"expiry_date"`
-		MRP         float64   `json:"mrp"`
-		Location    string    `json:"location"`
+// process moves one line, or every line that can make the move when lineID is 0.
+func (s *IndentService) process(ctx context.Context, indentID, lineID uint, status, userID, note string) (*domain.Indent, error) {
+	status, err := domain.NormalizeIndentStatus(status)
+	if err != nil {
+		return nil, err
 	}
+	note = strings.TrimSpace(note)
 
-	// PENDING -> PROCESSING (Suggest Batches)
-	if status == "PROCESSING" && indent.Status == "PENDING" {
-		item, err := s.itemRepo.GetByName(ctx, indent.ItemName)
+	// The status changes, the stock movements and the history rows commit or roll back together
+	var indent *domain.Indent
+	err = s.uow.Do(ctx, func(repos ports.TxRepositories) error {
+		var err error
+		indent, err = repos.Indents.GetByID(ctx, indentID)
 		if err != nil {
 			return err
 		}
 
-		batches, err := s.batchRepo.GetByItemID(ctx, item.ID)
-		if err != nil {
-			return err
+		var lines []*domain.IndentLine
+		if lineID != 0 {
+			line, err := indent.Line(lineID)
+			if err != nil {
+				return err
+			}
+			if err := line.CheckTransition(status); err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		} else {
+			for i := range indent.Lines {
+				if indent.Lines[i].CanTransitionTo(status) {
+					lines = append(lines, &indent.Lines[i])
+				}
+			}
+			if len(lines) == 0 {
+				return &domain.IndentTransitionError{IndentID: indent.ID, From: indent.Status, To: status}
+			}
 		}
 
-		// FIFO Logic Calculation
-		remainingQty := indent.Quantity
-		var suggestions []BatchDetail
-
-		for _, b := range batches {
-			if remainingQty <= 0 {
-				break
+		changes := make([]*domain.IndentStatusChange, 0, len(lines))
+		var short []*domain.IndentLine
+		for _, line := range lines {
+			from := line.Status
+			if err := s.applyTransition(ctx, repos, indent, line, status, userID); err != nil {
+				return fmt.Errorf("%s: %w", line.ItemName, err)
 			}
-			if b.Quantity > 0 {
-				take := b.Quantity
-				if take > remainingQty {
-					take = remainingQty
-				}
+			line.Status = status
+			if domain.IsDispatch(status) {
+				line.Status = line.DispatchStatus()
+			}
+			if line.Status == domain.IndentStatusPartiallyDispatched {
+				short = append(short, line)
+			}
+			if err := repos.Indents.UpdateLine(ctx, line); err != nil {
+				return err
+			}
+			changes = append(changes, &domain.IndentStatusChange{
+				IndentID:   indent.ID,
+				LineID:     &line.ID,
+				FromStatus: from,
+				ToStatus:   line.Status,
+				Actor:      userID,
+				Note:       lineNote(line.ItemName, note),
+				ChangedAt:  time.Now(),
+			})
+		}
 
-				mrp := 0.0
-				if b.MRP != nil {
-					mrp = *b.MRP
+		if len(short) > 0 {
+			backorder, err := raiseBackorder(ctx, repos, indent, short, userID)
+			if err != nil {
+				return err
+			}
+			for _, c := range changes {
+				line, _ := indent.Line(*c.LineID)
+				if line.Status == domain.IndentStatusPartiallyDispatched {
+					shortfall := fmt.Sprintf("%d of %d dispatched, backorder indent %d raised for the rest", line.DispatchedQuantity, line.Quantity, backorder.ID)
+					c.Note = lineNote(line.ItemName, strings.TrimSpace(shortfall+". "+note))
 				}
-
-				suggestions = append(suggestions, BatchDetail{
-					BatchNumber: b.BatchNumber,
-					Quantity:    take,
-					ExpiryDate:  b.ExpiryDate,
-					MRP:         mrp,
-					Location:    b.Location,
-				})
-				remainingQty -= take
 			}
 		}
 
-		detailsJSON, _ := json.Marshal(suggestions)
-		indent.Status = "PROCESSING"
-		indent.DispatchDetails = string(detailsJSON)
-		return s.repo.Update(ctx, indent)
+		indent.Summarize()
+		if err := repos.Indents.Update(ctx, indent); err != nil {
+			return err
+		}
+		for _, c := range changes {
+			if err := repos.Indents.AddStatusChange(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return indent, nil
+}
 
-	// PROCESSING -> DISPATCHED (Deduct Stock)
-	if status == "DISPATCHED" && indent.Status == "PROCESSING" {
-		// In a real app, we should probably re-verify availability here or rely on the JSON suggested
-		// For simplicity, we'll re-run the logic to ensure we deduct from current actual stock
-		// BUT, to ensure we dispatch exactly what was suggested, we should arguably use the stored JSON.
-		// Let's re-run FIFO to be safe against concurrent updates for now, similar to before.
+// lineNote prefixes a history note with the line's item, so whole-indent changes read line by line.
+func lineNote(itemName, note string) string {
+	if note == "" {
+		return itemName
+	}
+	return itemName + ": " + note
+}
 
-		item, err := s.itemRepo.GetByName(ctx, indent.ItemName)
+// applyTransition does the work a line's transition carries: reserving batches when processing
+// starts, deducting the reserved stock on dispatch and releasing it on rejection.
+// Other transitions only change the status.
+func (s *IndentService) applyTransition(ctx context.Context, repos ports.TxRepositories, indent *domain.Indent, line *domain.IndentLine, status, userID string) error {
+	switch status {
+	case domain.IndentStatusProcessing:
+		details, err := s.reserveStock(ctx, repos, line)
 		if err != nil {
 			return err
 		}
+		line.ApprovedQuantity = totalQuantity(details)
+		return setDispatchDetails(line, details)
 
-		batches, err := s.batchRepo.GetByItemID(ctx, item.ID)
+	case domain.IndentStatusDispatched, domain.IndentStatusPartiallyDispatched:
+		reservations, err := repos.Indents.ListReservations(ctx, line.ID)
+		if err != nil {
+			return err
+		}
+		// Indents that were already PROCESSING before reservations existed hold no stock yet,
+		// their pick list is only in the dispatch details
+		if len(reservations) == 0 {
+			if err := s.reserveLegacyDetails(ctx, repos, line); err != nil {
+				return err
+			}
+			if reservations, err = repos.Indents.ListReservations(ctx, line.ID); err != nil {
+				return err
+			}
+		}
+		if len(reservations) == 0 {
+			return fmt.Errorf("%w: indent %d line %d has nothing picked to dispatch", domain.ErrConflict, indent.ID, line.ID)
+		}
+		item, err := lineItem(ctx, repos.Items, line)
 		if err != nil {
 			return err
 		}
 
-		remainingQty := indent.Quantity
-		var dispatched []BatchDetail
-
-		for _, b := range batches {
-			if remainingQty <= 0 {
-				break
+		// Stock picked days ago may have run too close to expiry since
+		now := time.Now()
+		details := make([]batchDetail, 0, len(reservations))
+		for _, r := range reservations {
+			if r.Batch == nil {
+				return fmt.Errorf("%w: reserved batch %d no longer exists", domain.ErrConflict, r.BatchID)
 			}
-			if b.Quantity > 0 {
-				take := b.Quantity
-				if take > remainingQty {
-					take = remainingQty
-				}
+			if err := shelfLifeError(item, r.Batch, now); err != nil {
+				return fmt.Errorf("%w: %v, edit the pick list before dispatching", domain.ErrConflict, err)
+			}
+			if err := repos.Batches.ConsumeReserved(ctx, r.BatchID, r.Quantity); err != nil {
+				return err
+			}
+			if err := repos.Transactions.Create(ctx, &domain.InventoryTransaction{
+				ItemID:         r.Batch.ItemID,
+				BatchID:        &r.BatchID,
+				QuantityChange: -r.Quantity,
+				Reason:         "Indent",
+				ReferenceID:    fmt.Sprintf("IND-%d", indent.ID),
+				PerformedBy:    userID,
+				Timestamp:      time.Now(),
+				Notes:          fmt.Sprintf("Dispatched to Pharmacy %s", indent.PharmacyID),
+			}); err != nil {
+				return err
+			}
+			details = append(details, newBatchDetail(r.Batch, r.Quantity))
+		}
+		if err := repos.Indents.DeleteReservations(ctx, line.ID); err != nil {
+			return err
+		}
+		line.DispatchedQuantity = totalQuantity(details)
+		return setDispatchDetails(line, details)
 
-				// Deduct
-				b.Quantity -= take
-				if err := s.batchRepo.Update(ctx, &b); err != nil {
-					return err
-				}
+	case domain.IndentStatusRejected:
+		if line.Status != domain.IndentStatusProcessing {
+			return nil
+		}
+		return releaseStock(ctx, repos, line.ID)
+	}
+	return nil
+}
 
-				mrp := 0.0
-				if b.MRP != nil {
-					mrp = *b.MRP
-				}
+// releaseStock gives back everything the line holds.
+func releaseStock(ctx context.Context, repos ports.TxRepositories, lineID uint) error {
+	reservations, err := repos.Indents.ListReservations(ctx, lineID)
+	if err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		if err := repos.Batches.Release(ctx, r.BatchID, r.Quantity); err != nil {
+			return err
+		}
+	}
+	return repos.Indents.DeleteReservations(ctx, lineID)
+}
 
-				dispatched = append(dispatched, BatchDetail{
-					BatchNumber: b.BatchNumber,
-					Quantity:    take,
-					ExpiryDate:  b.ExpiryDate,
-					MRP:         mrp,
-					Location:    b.Location,
-				})
-
-				// Audit
-				tx := &domain.InventoryTransaction{
-					ItemID:         item.ID,
-					BatchID:        &b.ID,
-					QuantityChange: -take,
-					Reason:         "Indent",
-					ReferenceID:    fmt.Sprintf("IND-%d", indent.ID),
-					PerformedBy:    "System", // TODO: Get User
-					Notes:          fmt.Sprintf("Dispatched to Pharmacy %s", indent.PharmacyID),
-				}
-				s.txRepo.Create(ctx, tx)
+// holdBatch reserves qty of the batch for the line.
+func holdBatch(ctx context.Context, repos ports.TxRepositories, line *domain.IndentLine, b *domain.Batch, qty int) (batchDetail, error) {
+	if err := repos.Batches.Reserve(ctx, b.ID, qty); err != nil {
+		return batchDetail{}, err
+	}
+	err := repos.Indents.AddReservation(ctx, &domain.IndentReservation{
+		IndentID:     line.IndentID,
+		IndentLineID: line.ID,
+		BatchID:      b.ID,
+		Quantity:     qty,
+	})
+	return newBatchDetail(b, qty), err
+}
 
-				remainingQty -= take
-			}
+// lineItem finds the hospital item a line asks for.
+func lineItem(ctx context.Context, items ports.ItemRepository, line *domain.IndentLine) (*domain.Item, error) {
+	item, err := items.GetByName(ctx, line.ItemName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not stocked by the hospital store", domain.ErrUnprocessable, line.ItemName)
+	}
+	return item, nil
+}
+
+// reserveStock holds the line's quantity on free stock, taking batches in allocation order and
+// skipping those too close to expiry. A short line reserves what there is; the storekeeper sees
+// the shortfall in the details.
+func (s *IndentService) reserveStock(ctx context.Context, repos ports.TxRepositories, line *domain.IndentLine) ([]batchDetail, error) {
+	item, err := lineItem(ctx, repos.Items, line)
+	if err != nil {
+		return nil, err
+	}
+	batches, err := repos.Batches.GetByItemID(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	batches = s.dispatchable(item, batches)
+
+	remaining := line.Quantity
+	var details []batchDetail
+	for i := range batches {
+		b := &batches[i]
+		if remaining <= 0 {
+			break
+		}
+		free := b.Quantity - b.ReservedQuantity
+		if free <= 0 {
+			continue
+		}
+		take := free
+		if take > remaining {
+			take = remaining
+		}
+
+		detail, err := holdBatch(ctx, repos, line, b, take)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, detail)
+		remaining -= take
+	}
+	return details, nil
+}
+
+// dispatchable keeps the batches in stock that are good for the item's minimum shelf life,
+// in the order the allocation strategy takes them.
+func (s *IndentService) dispatchable(item *domain.Item, batches []domain.Batch) []domain.Batch {
+	cutoff := item.ShelfLifeCutoff(time.Now())
+	kept := make([]domain.Batch, 0, len(batches))
+	for _, b := range batches {
+		if b.Quantity > 0 && b.Dispatchable(cutoff) {
+			kept = append(kept, b)
 		}
+	}
+	s.allocation.Sort(kept)
+	return kept
+}
+
+// shelfLifeError says why the batch may not go to the pharmacy at now, or is nil when it may.
+func shelfLifeError(item *domain.Item, b *domain.Batch, now time.Time) error {
+	if b.Dispatchable(item.ShelfLifeCutoff(now)) {
+		return nil
+	}
+	expiry := b.ExpiryDate.Format("2006-01-02")
+	if !b.ExpiryDate.After(now) {
+		return fmt.Errorf("batch %s expired on %s", b.BatchNumber, expiry)
+	}
+	return fmt.Errorf("batch %s expires on %s, %s must have %d days of shelf life left", b.BatchNumber, expiry, item.Name, item.MinShelfLife())
+}
+
+// raiseBackorder opens a PENDING indent for what the short lines fell short by,
+// so the shortfall stays on the board until it is filled or rejected.
+func raiseBackorder(ctx context.Context, repos ports.TxRepositories, indent *domain.Indent, short []*domain.IndentLine, userID string) (*domain.Indent, error) {
+	backorder := &domain.Indent{
+		PharmacyID:    indent.PharmacyID,
+		BackorderOfID: &indent.ID,
+	}
+	for _, l := range short {
+		backorder.Lines = append(backorder.Lines, domain.IndentLine{
+			ItemName: l.ItemName,
+			Quantity: l.Shortfall(),
+			Status:   domain.IndentStatusPending,
+		})
+	}
+	backorder.Summarize()
+	if err := repos.Indents.Create(ctx, backorder); err != nil {
+		return nil, err
+	}
+	err := repos.Indents.AddStatusChange(ctx, &domain.IndentStatusChange{
+		IndentID:  backorder.ID,
+		ToStatus:  backorder.Status,
+		Actor:     userID,
+		Note:      fmt.Sprintf("Backorder for what indent %d was short", indent.ID),
+		ChangedAt: time.Now(),
+	})
+	return backorder, err
+}
+
+func totalQuantity(details []batchDetail) int {
+	total := 0
+	for _, d := range details {
+		total += d.Quantity
+	}
+	return total
+}
 
-		detailsJSON, _ := json.Marshal(dispatched)
-		indent.Status = "DISPATCHED"
-		indent.DispatchDetails = string(detailsJSON)
-		return s.repo.Update(ctx, indent)
+func setDispatchDetails(line *domain.IndentLine, details []batchDetail) error {
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return err
 	}
+	line.DispatchDetails = string(detailsJSON)
+	return nil
+}
 
-	// DISPATCHED -> FULFILLED (Confirmation)
-	if status == "FULFILLED" && indent.Status == "DISPATCHED" {
-		indent.Status = "FULFILLED"
-		return s.repo.Update(ctx, indent)
+// reserveLegacyDetails reserves the batches listed in dispatch details written before reservations
+// existed, matching them to current stock by batch number. Without such a list nothing was ever
+// confirmed, and dispatch must not pick batches on its own.
+func (s *IndentService) reserveLegacyDetails(ctx context.Context, repos ports.TxRepositories, line *domain.IndentLine) error {
+	var details []batchDetail
+	if err := json.Unmarshal([]byte(line.DispatchDetails), &details); err != nil || len(details) == 0 {
+		return fmt.Errorf("%w: no confirmed pick list, move the line back to PROCESSING or edit the pick list", domain.ErrConflict)
 	}
 
-	// Handle Rejection
-	if status == "REJECTED" {
-		indent.Status = "REJECTED"
-		return s.repo.Update(ctx, indent)
+	item, err := lineItem(ctx, repos.Items, line)
+	if err != nil {
+		return err
+	}
+	batches, err := repos.Batches.GetByItemID(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	byNumber := make(map[string]*domain.Batch, len(batches))
+	for i := range batches {
+		byNumber[strings.ToLower(batches[i].BatchNumber)] = &batches[i]
 	}
 
+	now := time.Now()
+	for _, d := range details {
+		b, ok := byNumber[strings.ToLower(d.BatchNumber)]
+		if !ok {
+			return fmt.Errorf("%w: picked batch %s is no longer in stock", domain.ErrConflict, d.BatchNumber)
+		}
+		if err := shelfLifeError(item, b, now); err != nil {
+			return fmt.Errorf("%w: %v, edit the pick list before dispatching", domain.ErrConflict, err)
+		}
+		if _, err := holdBatch(ctx, repos, line, b, d.Quantity); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func (s *IndentService) PickList(ctx context.Context, indentID, lineID uint) (*domain.PickList, error) {
+	indent, err := s.repo.GetByID(ctx, indentID)
+	if err != nil {
+		return nil, err
+	}
+	line, err := indent.Line(lineID)
+	if err != nil {
+		return nil, err
+	}
+	picks, err := s.repo.ListReservations(ctx, line.ID)
+	if err != nil {
+		return nil, err
+	}
+	list := &domain.PickList{Picks: picks, Candidates: []domain.Batch{}, Strategy: s.allocation.Name()}
+
+	item, err := s.itemRepo.GetByName(ctx, line.ItemName)
+	if err != nil {
+		// Nothing to pick from, the storekeeper can only reject the line
+		return list, nil
+	}
+	batches, err := s.batchRepo.GetByItemID(ctx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	list.Candidates = s.dispatchable(item, batches)
+	list.MinShelfLifeDays = item.MinShelfLife()
+	return list, nil
+}
+
+func (s *IndentService) UpdatePickList(ctx context.Context, indentID, lineID uint, picks []domain.IndentPick, userID string) (*domain.Indent, error) {
+	if len(picks) == 0 {
+		return nil, fmt.Errorf("%w: the pick list needs at least one batch", domain.ErrValidation)
+	}
+	seen := make(map[uint]bool, len(picks))
+	for i, p := range picks {
+		if p.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: pick %d: quantity must be positive", domain.ErrValidation, i+1)
+		}
+		if seen[p.BatchID] {
+			return nil, fmt.Errorf("%w: pick %d: batch %d is listed twice", domain.ErrValidation, i+1, p.BatchID)
+		}
+		seen[p.BatchID] = true
+	}
+
+	var indent *domain.Indent
+	err := s.uow.Do(ctx, func(repos ports.TxRepositories) error {
+		var err error
+		indent, err = repos.Indents.GetByID(ctx, indentID)
+		if err != nil {
+			return err
+		}
+		line, err := indent.Line(lineID)
+		if err != nil {
+			return err
+		}
+		if line.Status != domain.IndentStatusProcessing {
+			return fmt.Errorf("%w: indent %d line %d is %s, only a PROCESSING line can be re-picked",
+				domain.ErrConflict, indent.ID, line.ID, line.Status)
+		}
+		item, err := lineItem(ctx, repos.Items, line)
+		if err != nil {
+			return err
+		}
+
+		// Give back the old picks first, so a batch can be kept with a different quantity
+		if err := releaseStock(ctx, repos, line.ID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		total := 0
+		var details []batchDetail
+		var summary []string
+		for i, p := range picks {
+			b, err := repos.Batches.GetByID(ctx, p.BatchID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("%w: pick %d: batch %d does not exist", domain.ErrValidation, i+1, p.BatchID)
+			}
+			if err != nil {
+				return err
+			}
+			if b.ItemID != item.ID {
+				return fmt.Errorf("%w: pick %d: batch %s is not %s", domain.ErrValidation, i+1, b.BatchNumber, item.Name)
+			}
+			if err := shelfLifeError(item, b, now); err != nil {
+				return fmt.Errorf("%w: pick %d: %v", domain.ErrValidation, i+1, err)
+			}
+			detail, err := holdBatch(ctx, repos, line, b, p.Quantity)
+			if err != nil {
+				return fmt.Errorf("pick %d: %w", i+1, err)
+			}
+			details = append(details, detail)
+			summary = append(summary, fmt.Sprintf("%s x%d", b.BatchNumber, p.Quantity))
+			total += p.Quantity
+		}
+		if total > line.Quantity {
+			return fmt.Errorf("%w: %d picked but only %d requested", domain.ErrValidation, total, line.Quantity)
+		}
+
+		line.ApprovedQuantity = total
+		if err := setDispatchDetails(line, details); err != nil {
+			return err
+		}
+		if err := repos.Indents.UpdateLine(ctx, line); err != nil {
+			return err
+		}
+		indent.Summarize()
+		if err := repos.Indents.Update(ctx, indent); err != nil {
+			return err
+		}
+		return repos.Indents.AddStatusChange(ctx, &domain.IndentStatusChange{
+			IndentID:   indent.ID,
+			LineID:     &line.ID,
+			FromStatus: line.Status,
+			ToStatus:   line.Status,
+			Actor:      userID,
+			Note:       fmt.Sprintf("%s pick list changed: %s", line.ItemName, strings.Join(summary, ", ")),
+			ChangedAt:  time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return indent, nil
+}
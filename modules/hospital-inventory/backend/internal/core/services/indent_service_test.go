@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
+	"testing"
+	"time"
+)
+
+// A line that reached PROCESSING without reservations or a readable pick list has nothing
+// confirmed to dispatch, so dispatch must refuse rather than pick batches itself.
+func TestDispatchWithoutPickList(t *testing.T) {
+	for _, details := range []string{"", "null", "[]", "{not json"} {
+		t.Run(details, func(t *testing.T) {
+			db := newTestDB(t)
+			ctx := context.Background()
+
+			item := &domain.Item{Name: "Paracetamol 500mg", Unit: "Tablets"}
+			mustCreate(t, db, item)
+			batch := &domain.Batch{ItemID: item.ID, BatchNumber: "B1", Quantity: 100, ExpiryDate: time.Now().AddDate(1, 0, 0)}
+			mustCreate(t, db, batch)
+			indent := &domain.Indent{
+				PharmacyID: "PH-1",
+				Status:     domain.IndentStatusProcessing,
+				Lines: []domain.IndentLine{{
+					ItemName:        item.Name,
+					Quantity:        10,
+					Status:          domain.IndentStatusProcessing,
+					DispatchDetails: details,
+				}},
+			}
+			mustCreate(t, db, indent)
+
+			service := NewIndentService(repositories.NewGormIndentRepository(db), repositories.NewGormItemRepository(db),
+				repositories.NewGormBatchRepository(db), repositories.NewGormUnitOfWork(db), nil)
+			_, err := service.ProcessIndentLine(ctx, indent.ID, indent.Lines[0].ID, domain.IndentStatusDispatched, "store", "")
+			if !errors.Is(err, domain.ErrConflict) {
+				t.Fatalf("dispatch error = %v, want ErrConflict", err)
+			}
+
+			var after domain.Batch
+			if err := db.First(&after, batch.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if after.Quantity != 100 || after.ReservedQuantity != 0 {
+				t.Errorf("batch quantity %d reserved %d, want 100 and 0", after.Quantity, after.ReservedQuantity)
+			}
+			var movements, reservations int64
+			db.Model(&domain.InventoryTransaction{}).Count(&movements)
+			db.Model(&domain.IndentReservation{}).Count(&reservations)
+			if movements != 0 || reservations != 0 {
+				t.Errorf("%d stock movements and %d reservations recorded, want none", movements, reservations)
+			}
+
+			var line domain.IndentLine
+			if err := db.First(&line, indent.Lines[0].ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if line.Status != domain.IndentStatusProcessing || line.DispatchedQuantity != 0 {
+				t.Errorf("line is %s with %d dispatched, want it left PROCESSING", line.Status, line.DispatchedQuantity)
+			}
+		})
+	}
+}
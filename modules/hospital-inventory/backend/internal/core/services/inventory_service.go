@@ -2,28 +2,87 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hospital-inventory/internal/core/domain"
 	"hospital-inventory/internal/core/ports"
+	"sort"
 	"strings"
 	"time"
 )
 
 type InventoryService struct {
-	itemRepo  ports.ItemRepository
-	batchRepo ports.BatchRepository
-	txRepo    ports.TransactionRepository
+	itemRepo     ports.ItemRepository
+	batchRepo    ports.BatchRepository
+	txRepo       ports.TransactionRepository
+	categoryRepo ports.CategoryRepository
+	supplierRepo ports.SupplierRepository
 }
 
-func NewInventoryService(itemRepo ports.ItemRepository, batchRepo ports.BatchRepository, txRepo ports.TransactionRepository) *InventoryService {
+func NewInventoryService(itemRepo ports.ItemRepository, batchRepo ports.BatchRepository, txRepo ports.TransactionRepository, categoryRepo ports.CategoryRepository, supplierRepo ports.SupplierRepository) *InventoryService {
 	return &InventoryService{
-		itemRepo:  itemRepo,
-		batchRepo: batchRepo,
-		txRepo:    txRepo,
+		itemRepo:     itemRepo,
+		batchRepo:    batchRepo,
+		txRepo:       txRepo,
+		categoryRepo: categoryRepo,
+		supplierRepo: supplierRepo,
 	}
 }
 
+// resolveSupplier checks a batch's supplier exists and attaches it to the batch.
+func (s *InventoryService) resolveSupplier(ctx context.Context, batch *domain.Batch) error {
+	if batch.SupplierID == nil {
+		batch.Supplier = nil
+		return nil
+	}
+	supplier, err := s.supplierRepo.GetByID(ctx, *batch.SupplierID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: supplier %d does not exist", domain.ErrValidation, *batch.SupplierID)
+	}
+	if err != nil {
+		return err
+	}
+	batch.Supplier = supplier
+	return nil
+}
+
+// resolveCategory checks the item's category exists and attaches it, so responses show the new category.
+func (s *InventoryService) resolveCategory(ctx context.Context, item *domain.Item) error {
+	if item.CategoryID == nil {
+		item.Category = domain.Category{}
+		return nil
+	}
+	category, err := s.categoryRepo.GetByID(ctx, *item.CategoryID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: category %d does not exist", domain.ErrValidation, *item.CategoryID)
+	}
+	if err != nil {
+		return err
+	}
+	item.Category = *category
+	return nil
+}
+
+// checkShelfLife rejects a negative minimum shelf life; nil means the category's applies.
+func checkShelfLife(item *domain.Item) error {
+	if item.MinShelfLifeDays != nil && *item.MinShelfLifeDays < 0 {
+		return fmt.Errorf("%w: minimum shelf life cannot be negative", domain.ErrValidation)
+	}
+	return nil
+}
+
 func (s *InventoryService) CreateItem(ctx context.Context, item *domain.Item, initialBatch *domain.Batch) error {
+	if err := checkShelfLife(item); err != nil {
+		return err
+	}
+	if err := s.resolveCategory(ctx, item); err != nil {
+		return err
+	}
+	if initialBatch != nil {
+		if err := s.resolveSupplier(ctx, initialBatch); err != nil {
+			return err
+		}
+	}
 	if err := s.itemRepo.Create(ctx, item); err != nil {
 		return err
 	}
@@ -56,29 +115,53 @@ func (s *InventoryService) CreateItem(ctx context.Context, item *domain.Item, in
 	return nil
 }
 
-func (s *InventoryService) UpdateBatch(ctx context.Context, batch *domain.Batch, reason string, userID string) error {
+// UpdateBatch applies a manual edit on top of the stored batch, so fields the edit leaves out
+// (its item, supplier and purchase price) are kept.
+func (s *InventoryService) UpdateBatch(ctx context.Context, id uint, update domain.BatchUpdate, reason string, userID string) (*domain.Batch, error) {
 	// 1. Get existing batch for quantity comparison
-	oldBatch, err := s.batchRepo.GetByID(ctx, batch.ID)
+	oldBatch, err := s.batchRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if update.Quantity < oldBatch.ReservedQuantity {
+		return nil, fmt.Errorf("%w: %d of batch %s is reserved for indents", domain.ErrConflict, oldBatch.ReservedQuantity, oldBatch.BatchNumber)
+	}
+
+	batch := *oldBatch
+	batch.BatchNumber = update.BatchNumber
+	batch.Quantity = update.Quantity
+	batch.ExpiryDate = update.ExpiryDate
+	batch.Location = update.Location
+	batch.MRP = update.MRP
+	if update.SupplierID != nil {
+		batch.SupplierID = update.SupplierID
+		if *update.SupplierID == 0 {
+			batch.SupplierID = nil
+		}
+	}
+	if update.PurchasePrice != nil {
+		batch.PurchasePrice = *update.PurchasePrice
+	}
+	if err := s.resolveSupplier(ctx, &batch); err != nil {
+		return nil, err
 	}
 
 	qtyDiff := batch.Quantity - oldBatch.Quantity
 
 	// 2. Update Batch
-	if err := s.batchRepo.Update(ctx, batch); err != nil {
-		return err
+	if err := s.batchRepo.Update(ctx, &batch); err != nil {
+		return nil, err
 	}
 
 	// 3. Update Item Stock if quantity changed
 	if qtyDiff != 0 {
 		item, err := s.GetItem(ctx, batch.ItemID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		item.TotalQuantity += qtyDiff
 		if err := s.itemRepo.Update(ctx, item); err != nil {
-			return err
+			return nil, err
 		}
 
 		// 4. Log Transaction
@@ -90,7 +173,7 @@ func (s *InventoryService) UpdateBatch(ctx context.Context, batch *domain.Batch,
 			Timestamp:      time.Now(),
 		})
 	}
-	return nil
+	return &batch, nil
 }
 
 func (s *InventoryService) DeleteBatch(ctx context.Context, batchID uint, userID string) error {
@@ -99,6 +182,9 @@ func (s *InventoryService) DeleteBatch(ctx context.Context, batchID uint, userID
 	if err != nil {
 		return err
 	}
+	if batch.ReservedQuantity > 0 {
+		return fmt.Errorf("%w: %d of batch %s is reserved for indents", domain.ErrConflict, batch.ReservedQuantity, batch.BatchNumber)
+	}
 
 	// 2. Delete Batch
 	if err := s.batchRepo.Delete(ctx, batchID); err != nil {
@@ -132,6 +218,12 @@ func (s *InventoryService) GetItem(ctx context.Context, id uint) (*domain.Item,
 }
 
 func (s *InventoryService) UpdateItem(ctx context.Context, item *domain.Item) error {
+	if err := checkShelfLife(item); err != nil {
+		return err
+	}
+	if err := s.resolveCategory(ctx, item); err != nil {
+		return err
+	}
 	if err := s.itemRepo.Update(ctx, item); err != nil {
 		return err
 	}
@@ -193,6 +285,10 @@ func (s *InventoryService) GetKnowledgeBase(ctx context.Context) ([]domain.Item,
 }
 
 func (s *InventoryService) AddBatch(ctx context.Context, batch *domain.Batch, userID string) error {
+	if err := s.resolveSupplier(ctx, batch); err != nil {
+		return err
+	}
+
 	// 1. Save Batch
 	if err := s.batchRepo.Create(ctx, batch); err != nil {
 		return err
@@ -216,6 +312,9 @@ func (s *InventoryService) ListTransactions(ctx context.Context) ([]domain.Inven
 	return s.txRepo.List(ctx)
 }
 
+// GetDashboardStats totals the dashboard figures overall and per category. An item is low on stock
+// when the quantity of all its batches, expired ones included, is below its threshold; this is the
+// total_quantity the item list shows.
 func (s *InventoryService) GetDashboardStats(ctx context.Context) (*domain.DashboardStats, error) {
 	// 1. Get All Items
 	items, err := s.itemRepo.List(ctx)
@@ -228,29 +327,61 @@ func (s *InventoryService) GetDashboardStats(ctx context.Context) (*domain.Dashb
 		TotalValue:    0,
 		LowStockItems: 0,
 		ExpiredItems:  0,
+		Categories:    []domain.CategoryStats{},
 	}
 
 	now := time.Now()
+	byCategory := make(map[uint]*domain.CategoryStats)
 
 	for _, item := range items {
-		// Low Stock Check
-		if item.TotalQuantity < item.Threshold {
-			stats.LowStockItems++
+		// Items without a category are grouped under ID 0
+		var categoryKey uint
+		if item.CategoryID != nil {
+			categoryKey = *item.CategoryID
+		}
+		cat, ok := byCategory[categoryKey]
+		if !ok {
+			cat = &domain.CategoryStats{CategoryID: item.CategoryID, Name: item.Category.Name}
+			if item.CategoryID == nil || cat.Name == "" {
+				cat.Name = "Uncategorized"
+			}
+			byCategory[categoryKey] = cat
 		}
+		cat.ItemCount++
 
+		// List does not fill TotalQuantity, so sum the batches here
+		quantity := 0
 		for _, batch := range item.Batches {
+			quantity += batch.Quantity
+
 			// Total Value Calculation
 			if batch.MRP != nil {
-				stats.TotalValue += float64(batch.Quantity) * (*batch.MRP)
+				value := float64(batch.Quantity) * (*batch.MRP)
+				stats.TotalValue += value
+				cat.StockValue += value
 			}
 
 			// Expired Check
 			if batch.ExpiryDate.Before(now) {
 				stats.ExpiredItems++
+				cat.ExpiredItems++
 			}
 		}
+
+		// Low Stock Check
+		if quantity < item.Threshold {
+			stats.LowStockItems++
+			cat.LowStockItems++
+		}
 	}
 
+	for _, cat := range byCategory {
+		stats.Categories = append(stats.Categories, *cat)
+	}
+	sort.Slice(stats.Categories, func(i, j int) bool {
+		return stats.Categories[i].Name < stats.Categories[j].Name
+	})
+
 	return stats, nil
 }
 
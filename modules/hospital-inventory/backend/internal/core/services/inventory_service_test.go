@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestGetDashboardStats(t *testing.T) {
+	db := newTestDB(t)
+	drugs := &domain.Category{Name: "Drugs"}
+	mustCreate(t, db, drugs)
+	items := map[string]*domain.Item{
+		"short":          {Name: "Paracetamol", CategoryID: &drugs.ID, Threshold: 10},
+		"at threshold":   {Name: "Ibuprofen", CategoryID: &drugs.ID, Threshold: 10},
+		"no threshold":   {Name: "Cotton", Threshold: 0},
+		"out of stock":   {Name: "Gauze", Threshold: 5},
+		"above, expired": {Name: "Syringe", Threshold: 5},
+	}
+	for _, item := range items {
+		mustCreate(t, db, item)
+	}
+	live, expired := time.Now().AddDate(1, 0, 0), time.Now().AddDate(0, 0, -1)
+	mrp := 2.0
+	mustCreate(t, db,
+		&domain.Batch{ItemID: items["short"].ID, BatchNumber: "P1", Quantity: 4, MRP: &mrp, ExpiryDate: live},
+		&domain.Batch{ItemID: items["short"].ID, BatchNumber: "P2", Quantity: 5, MRP: &mrp, ExpiryDate: live},
+		&domain.Batch{ItemID: items["at threshold"].ID, BatchNumber: "I1", Quantity: 10, ExpiryDate: live},
+		&domain.Batch{ItemID: items["no threshold"].ID, BatchNumber: "C1", Quantity: 0, ExpiryDate: live},
+		&domain.Batch{ItemID: items["out of stock"].ID, BatchNumber: "G1", Quantity: 0, ExpiryDate: live},
+		// Expired stock still counts towards the total, as on the item list
+		&domain.Batch{ItemID: items["above, expired"].ID, BatchNumber: "S1", Quantity: 6, ExpiryDate: expired},
+	)
+
+	service := NewInventoryService(repositories.NewGormItemRepository(db), repositories.NewGormBatchRepository(db),
+		repositories.NewGormTransactionRepository(db), repositories.NewGormCategoryRepository(db), repositories.NewGormSupplierRepository(db))
+	stats, err := service.GetDashboardStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Paracetamol (9 of 10) and Gauze (0 of 5)
+	if stats.TotalItems != 5 || stats.LowStockItems != 2 || stats.ExpiredItems != 1 || stats.TotalValue != 18 {
+		t.Errorf("stats = %+v, want 5 items, 2 low on stock, 1 expired batch and a value of 18", stats)
+	}
+
+	want := map[string]domain.CategoryStats{
+		"Drugs":         {Name: "Drugs", ItemCount: 2, StockValue: 18, LowStockItems: 1},
+		"Uncategorized": {Name: "Uncategorized", ItemCount: 3, LowStockItems: 1, ExpiredItems: 1},
+	}
+	if len(stats.Categories) != len(want) {
+		t.Fatalf("categories = %+v, want Drugs and Uncategorized", stats.Categories)
+	}
+	for _, got := range stats.Categories {
+		w := want[got.Name]
+		got.CategoryID = nil
+		if got != w {
+			t.Errorf("category %s = %+v, want %+v", got.Name, got, w)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// writeOffReasons remove stock without it being used, so they do not count as consumption.
+var writeOffReasons = []string{"Batch Deleted", "Item Deleted", "Expired", "Correction", "Damaged"}
+
+// openOrderStatuses are the supply order states whose unreceived lines count as on order.
+// Drafts are included so accepted suggestions are not suggested again.
+var openOrderStatuses = []string{domain.OrderStatusDraft, domain.OrderStatusApproved, domain.OrderStatusSent, domain.OrderStatusPartiallyReceived}
+
+type ReorderService struct {
+	itemRepo  ports.ItemRepository
+	txRepo    ports.TransactionRepository
+	orderRepo ports.SupplyOrderRepository
+}
+
+func NewReorderService(itemRepo ports.ItemRepository, txRepo ports.TransactionRepository, orderRepo ports.SupplyOrderRepository) ports.ReorderService {
+	return &ReorderService{
+		itemRepo:  itemRepo,
+		txRepo:    txRepo,
+		orderRepo: orderRepo,
+	}
+}
+
+// SuggestReorders works out, per item:
+//
+//	average daily usage = outflow over the window / window days
+//	safety stock        = usage x safety days
+//	reorder point       = usage x supplier lead time + safety stock, and never below the item's threshold
+//	suggested quantity  = reorder point + usage x cover days - (stock + on order), at least the item's restock level
+//
+// An item is suggested once stock plus what is already on order has fallen to its reorder point.
+func (s *ReorderService) SuggestReorders(ctx context.Context, params domain.ReorderParams) (*domain.ReorderReport, error) {
+	if params.WindowDays <= 0 {
+		params.WindowDays = domain.DefaultReorderWindowDays
+	}
+	if params.SafetyDays < 0 {
+		params.SafetyDays = domain.DefaultSafetyDays
+	}
+	if params.CoverDays <= 0 {
+		params.CoverDays = domain.DefaultCoverDays
+	}
+
+	now := time.Now()
+	report := &domain.ReorderReport{
+		From:       now.AddDate(0, 0, -params.WindowDays),
+		To:         now,
+		WindowDays: params.WindowDays,
+		SafetyDays: params.SafetyDays,
+		CoverDays:  params.CoverDays,
+		Suppliers:  []domain.SupplierReorder{},
+	}
+
+	items, err := s.itemRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	consumed, err := s.txRepo.SumOutflows(ctx, report.From, report.To, writeOffReasons)
+	if err != nil {
+		return nil, err
+	}
+	onOrder, err := s.orderRepo.OutstandingByItem(ctx, openOrderStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[uint]*domain.SupplierReorder)
+	var unassigned *domain.SupplierReorder
+	for i := range items {
+		item := &items[i]
+		supplier, unitCost := lastSupply(item)
+
+		leadTime := domain.DefaultLeadTimeDays
+		if supplier != nil && supplier.LeadTimeDays > 0 {
+			leadTime = supplier.LeadTimeDays
+		}
+
+		suggestion, ok := suggestReorder(item, consumed[item.ID], onOrder[item.ID], leadTime, params, now)
+		if !ok {
+			continue
+		}
+		suggestion.UnitCost = unitCost
+
+		var group *domain.SupplierReorder
+		if supplier == nil {
+			if unassigned == nil {
+				unassigned = &domain.SupplierReorder{SupplierName: "Unassigned", LeadTimeDays: domain.DefaultLeadTimeDays}
+			}
+			group = unassigned
+		} else if group = groups[supplier.ID]; group == nil {
+			id := supplier.ID
+			group = &domain.SupplierReorder{SupplierID: &id, SupplierName: supplier.Name, LeadTimeDays: leadTime}
+			groups[supplier.ID] = group
+		}
+		group.Items = append(group.Items, suggestion)
+		group.EstimatedCost += float64(suggestion.Quantity) * suggestion.UnitCost
+	}
+
+	for _, group := range groups {
+		report.Suppliers = append(report.Suppliers, *group)
+	}
+	sort.Slice(report.Suppliers, func(i, j int) bool {
+		return strings.ToLower(report.Suppliers[i].SupplierName) < strings.ToLower(report.Suppliers[j].SupplierName)
+	})
+	if unassigned != nil {
+		report.Suppliers = append(report.Suppliers, *unassigned)
+	}
+
+	for i := range report.Suppliers {
+		group := &report.Suppliers[i]
+		group.EstimatedCost = domain.RoundMoney(group.EstimatedCost)
+		// Most urgent first
+		sort.SliceStable(group.Items, func(a, b int) bool {
+			return coverOf(group.Items[a]) < coverOf(group.Items[b])
+		})
+	}
+	return report, nil
+}
+
+func suggestReorder(item *domain.Item, consumed, onOrder, leadTime int, params domain.ReorderParams, now time.Time) (domain.ReorderSuggestion, bool) {
+	// Stock reserved for dispatch is already spoken for
+	stock := 0
+	for _, b := range item.Batches {
+		if b.ExpiryDate.After(now) {
+			stock += b.Quantity - b.ReservedQuantity
+		}
+	}
+
+	usage := float64(consumed) / float64(params.WindowDays)
+	safety := ceil(usage * float64(params.SafetyDays))
+	reorderPoint := ceil(usage*float64(leadTime)) + safety
+	if item.Threshold > reorderPoint {
+		reorderPoint = item.Threshold
+	}
+
+	available := stock + onOrder
+	if reorderPoint == 0 || available > reorderPoint {
+		return domain.ReorderSuggestion{}, false
+	}
+
+	quantity := reorderPoint + ceil(usage*float64(params.CoverDays)) - available
+	if quantity < item.RestockLevel {
+		quantity = item.RestockLevel
+	}
+	if quantity <= 0 {
+		return domain.ReorderSuggestion{}, false
+	}
+
+	suggestion := domain.ReorderSuggestion{
+		ItemID:        item.ID,
+		ItemName:      item.Name,
+		Unit:          item.Unit,
+		CurrentStock:  stock,
+		OnOrder:       onOrder,
+		Consumed:      consumed,
+		AvgDailyUsage: math.Round(usage*100) / 100,
+		LeadTimeDays:  leadTime,
+		SafetyStock:   safety,
+		ReorderPoint:  reorderPoint,
+		Quantity:      quantity,
+	}
+	if usage > 0 {
+		cover := math.Round(float64(available)/usage*10) / 10
+		suggestion.DaysOfCover = &cover
+		suggestion.Reason = fmt.Sprintf("%.1f days of stock left, lead time is %d days", cover, leadTime)
+	} else {
+		suggestion.Reason = fmt.Sprintf("Stock %d is at or below the threshold of %d", available, item.Threshold)
+	}
+	return suggestion, true
+}
+
+// lastSupply returns the supplier of the most recently added batch that has one,
+// and the latest known purchase price, falling back to the item's base price.
+func lastSupply(item *domain.Item) (*domain.Supplier, float64) {
+	var supplier *domain.Supplier
+	unitCost := item.Price
+	var supplierSeen, priceSeen time.Time
+	for _, b := range item.Batches {
+		if b.Supplier != nil && b.CreatedAt.After(supplierSeen) {
+			supplier, supplierSeen = b.Supplier, b.CreatedAt
+		}
+		if b.PurchasePrice > 0 && b.CreatedAt.After(priceSeen) {
+			unitCost, priceSeen = b.PurchasePrice, b.CreatedAt
+		}
+	}
+	return supplier, unitCost
+}
+
+// coverOf sorts items with no usage history after those running down.
+func coverOf(s domain.ReorderSuggestion) float64 {
+	if s.DaysOfCover == nil {
+		return math.MaxFloat64
+	}
+	return *s.DaysOfCover
+}
+
+func ceil(v float64) int {
+	return int(math.Ceil(v - 1e-9))
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"hospital-inventory/internal/adapters/repositories"
+	"hospital-inventory/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestSuggestReorder(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	params := domain.ReorderParams{WindowDays: 30, SafetyDays: 7, CoverDays: 30}
+	batch := func(qty, reserved int, expiry time.Time) domain.Batch {
+		return domain.Batch{Quantity: qty, ReservedQuantity: reserved, ExpiryDate: expiry}
+	}
+	live := now.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name         string
+		item         domain.Item
+		consumed     int
+		onOrder      int
+		wantOK       bool
+		wantStock    int
+		wantPoint    int
+		wantQuantity int
+		wantCover    float64 // 0 when there is no usage
+	}{
+		{
+			// 10 a day: 70 safety stock plus 70 over the lead time, then 300 to cover 30 days
+			name:     "reorder point from usage",
+			item:     domain.Item{Batches: []domain.Batch{batch(100, 0, live)}},
+			consumed: 300, wantOK: true, wantStock: 100, wantPoint: 140, wantQuantity: 340, wantCover: 10,
+		},
+		{
+			name:     "reserved stock is not available",
+			item:     domain.Item{Batches: []domain.Batch{batch(200, 80, live)}},
+			consumed: 300, wantOK: true, wantStock: 120, wantPoint: 140, wantQuantity: 320, wantCover: 12,
+		},
+		{
+			name:     "expired stock is not available",
+			item:     domain.Item{Batches: []domain.Batch{batch(500, 0, now.AddDate(0, 0, -1)), batch(100, 0, live)}},
+			consumed: 300, wantOK: true, wantStock: 100, wantPoint: 140, wantQuantity: 340, wantCover: 10,
+		},
+		{
+			name:     "stock on order counts",
+			item:     domain.Item{Batches: []domain.Batch{batch(100, 0, live)}},
+			consumed: 300, onOrder: 50,
+		},
+		{
+			name:     "threshold is the floor of the reorder point",
+			item:     domain.Item{Threshold: 200, Batches: []domain.Batch{batch(150, 0, live)}},
+			consumed: 300, wantOK: true, wantStock: 150, wantPoint: 200, wantQuantity: 350, wantCover: 15,
+		},
+		{
+			name:   "threshold without usage",
+			item:   domain.Item{Threshold: 20, Batches: []domain.Batch{batch(15, 0, live)}},
+			wantOK: true, wantStock: 15, wantPoint: 20, wantQuantity: 5,
+		},
+		{
+			name:   "restock level is the minimum order",
+			item:   domain.Item{Threshold: 20, RestockLevel: 50, Batches: []domain.Batch{batch(15, 0, live)}},
+			wantOK: true, wantStock: 15, wantPoint: 20, wantQuantity: 50,
+		},
+		{
+			name: "above the threshold",
+			item: domain.Item{Threshold: 20, RestockLevel: 50, Batches: []domain.Batch{batch(21, 0, live)}},
+		},
+		{
+			name: "no usage and no threshold",
+			item: domain.Item{RestockLevel: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := suggestReorder(&tt.item, tt.consumed, tt.onOrder, 7, params, now)
+			if ok != tt.wantOK {
+				t.Fatalf("suggested = %v, want %v (%+v)", ok, tt.wantOK, got)
+			}
+			if !ok {
+				return
+			}
+			if got.CurrentStock != tt.wantStock || got.ReorderPoint != tt.wantPoint || got.Quantity != tt.wantQuantity {
+				t.Errorf("stock %d, reorder point %d, quantity %d, want %d, %d and %d",
+					got.CurrentStock, got.ReorderPoint, got.Quantity, tt.wantStock, tt.wantPoint, tt.wantQuantity)
+			}
+			switch {
+			case tt.wantCover == 0 && got.DaysOfCover != nil:
+				t.Errorf("days of cover = %v, want none without usage", *got.DaysOfCover)
+			case tt.wantCover != 0 && (got.DaysOfCover == nil || *got.DaysOfCover != tt.wantCover):
+				t.Errorf("days of cover = %v, want %v", got.DaysOfCover, tt.wantCover)
+			}
+		})
+	}
+}
+
+func TestLastSupply(t *testing.T) {
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	acme := &domain.Supplier{BaseModel: domain.BaseModel{ID: 1}, Name: "Acme"}
+	medline := &domain.Supplier{BaseModel: domain.BaseModel{ID: 2}, Name: "Medline"}
+	batch := func(days int, supplier *domain.Supplier, price float64) domain.Batch {
+		return domain.Batch{BaseModel: domain.BaseModel{CreatedAt: first.AddDate(0, 0, days)}, Supplier: supplier, PurchasePrice: price}
+	}
+
+	tests := []struct {
+		name         string
+		batches      []domain.Batch
+		wantSupplier *domain.Supplier
+		wantCost     float64
+	}{
+		{"no batches", nil, nil, 4},
+		{"latest batch wins", []domain.Batch{batch(2, medline, 3.5), batch(0, acme, 3)}, medline, 3.5},
+		{"batches without a supplier or price are skipped", []domain.Batch{batch(0, acme, 3), batch(1, medline, 0), batch(2, nil, 2.75)}, medline, 2.75},
+		{"no known price falls back to the item price", []domain.Batch{batch(0, acme, 0)}, acme, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supplier, cost := lastSupply(&domain.Item{Price: 4, Batches: tt.batches})
+			if supplier != tt.wantSupplier || cost != tt.wantCost {
+				t.Errorf("lastSupply = %v, %v, want %v, %v", supplier, cost, tt.wantSupplier, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestSuggestReorders(t *testing.T) {
+	db := newTestDB(t)
+	supplier := &domain.Supplier{Name: "Acme", LeadTimeDays: 7}
+	mustCreate(t, db, supplier)
+	slow := &domain.Item{Name: "Gauze"}
+	fast := &domain.Item{Name: "Syringe"}
+	idle := &domain.Item{Name: "Splint", Threshold: 20}
+	mustCreate(t, db, slow, fast, idle)
+	expiry := time.Now().AddDate(1, 0, 0)
+	mustCreate(t, db,
+		&domain.Batch{ItemID: slow.ID, BatchNumber: "G1", Quantity: 100, ExpiryDate: expiry, SupplierID: &supplier.ID},
+		&domain.Batch{ItemID: fast.ID, BatchNumber: "S1", Quantity: 100, ExpiryDate: expiry, SupplierID: &supplier.ID},
+		&domain.Batch{ItemID: idle.ID, BatchNumber: "P1", Quantity: 15, ExpiryDate: expiry, SupplierID: &supplier.ID},
+	)
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	entry := func(item *domain.Item, qty int, reason string) *domain.InventoryTransaction {
+		return &domain.InventoryTransaction{ItemID: item.ID, QuantityChange: -qty, Reason: reason, Timestamp: yesterday}
+	}
+	mustCreate(t, db, entry(slow, 300, "Indent"), entry(fast, 600, "Indent"))
+	// Write-offs are not usage
+	for _, reason := range writeOffReasons {
+		mustCreate(t, db, entry(slow, 1000, reason), entry(idle, 1000, reason))
+	}
+
+	service := NewReorderService(repositories.NewGormItemRepository(db), repositories.NewGormTransactionRepository(db), repositories.NewSupplyOrderRepository(db))
+	report, err := service.SuggestReorders(context.Background(), domain.ReorderParams{WindowDays: 30, SafetyDays: 7, CoverDays: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Suppliers) != 1 || report.Suppliers[0].SupplierName != "Acme" {
+		t.Fatalf("suppliers = %+v, want Acme alone", report.Suppliers)
+	}
+
+	// Least cover first, items with no usage last
+	want := []struct {
+		name     string
+		consumed int
+		quantity int
+	}{{"Syringe", 600, 780}, {"Gauze", 300, 340}, {"Splint", 0, 5}}
+	got := report.Suppliers[0].Items
+	if len(got) != len(want) {
+		t.Fatalf("suggested %d items, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].ItemName != w.name || got[i].Consumed != w.consumed || got[i].Quantity != w.quantity {
+			t.Errorf("suggestion %d = %s consumed %d, order %d, want %s consumed %d, order %d",
+				i, got[i].ItemName, got[i].Consumed, got[i].Quantity, w.name, w.consumed, w.quantity)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"hospital-inventory/internal/core/domain"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens a fresh, migrated SQLite database that lives as long as the test.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.AutoMigrate(&domain.Category{}, &domain.Supplier{}, &domain.Item{}, &domain.Batch{}, &domain.InventoryTransaction{}, &domain.EmergencyRequest{}, &domain.Indent{}, &domain.IndentLine{}, &domain.IndentStatusChange{}, &domain.IndentReservation{}, &domain.SupplyOrder{}, &domain.SupplyOrderLine{}, &domain.SupplyOrderStatusChange{}, &domain.GoodsReceipt{}, &domain.GoodsReceiptLine{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// mustCreate inserts each value or fails the test.
+func mustCreate(t *testing.T, db *gorm.DB, values ...interface{}) {
+	t.Helper()
+	for _, v := range values {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
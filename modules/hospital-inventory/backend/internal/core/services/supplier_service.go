@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// gstinRegex checks the GSTIN layout: state code, PAN, entity number, "Z" and a check character.
+var gstinRegex = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+type SupplierService struct {
+	repo ports.SupplierRepository
+}
+
+func NewSupplierService(repo ports.SupplierRepository) ports.SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+func (s *SupplierService) CreateSupplier(ctx context.Context, supplier *domain.Supplier) error {
+	if err := s.validate(ctx, supplier); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, supplier)
+}
+
+func (s *SupplierService) GetSupplier(ctx context.Context, id uint) (*domain.Supplier, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *SupplierService) FindSupplier(ctx context.Context, id *uint, name string) (*domain.Supplier, error) {
+	var supplier *domain.Supplier
+	var err error
+	switch {
+	case id != nil:
+		supplier, err = s.repo.GetByID(ctx, *id)
+	case strings.TrimSpace(name) != "":
+		supplier, err = s.repo.GetByName(ctx, strings.TrimSpace(name))
+	default:
+		return nil, fmt.Errorf("%w: supplier is required", domain.ErrValidation)
+	}
+	// An unknown supplier on an order or batch is bad input, not a missing resource
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown supplier, add it under /api/suppliers first", domain.ErrValidation)
+	}
+	return supplier, err
+}
+
+func (s *SupplierService) UpdateSupplier(ctx context.Context, supplier *domain.Supplier) error {
+	existing, err := s.repo.GetByID(ctx, supplier.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(ctx, supplier); err != nil {
+		return err
+	}
+	supplier.CreatedAt = existing.CreatedAt
+	return s.repo.Update(ctx, supplier)
+}
+
+func (s *SupplierService) DeleteSupplier(ctx context.Context, id uint) error {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *SupplierService) ListSuppliers(ctx context.Context) ([]domain.Supplier, error) {
+	return s.repo.List(ctx)
+}
+
+// validate normalises the supplier fields and checks them, including that the name is not taken.
+func (s *SupplierService) validate(ctx context.Context, supplier *domain.Supplier) error {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	supplier.GSTIN = strings.ToUpper(strings.TrimSpace(supplier.GSTIN))
+	supplier.Email = strings.TrimSpace(supplier.Email)
+
+	if supplier.Name == "" {
+		return fmt.Errorf("%w: supplier name is required", domain.ErrValidation)
+	}
+	if supplier.GSTIN != "" && !gstinRegex.MatchString(supplier.GSTIN) {
+		return fmt.Errorf("%w: %q is not a valid GSTIN", domain.ErrValidation, supplier.GSTIN)
+	}
+	if supplier.Email != "" {
+		if _, err := mail.ParseAddress(supplier.Email); err != nil {
+			return fmt.Errorf("%w: %q is not a valid email address", domain.ErrValidation, supplier.Email)
+		}
+	}
+	if supplier.LeadTimeDays < 0 {
+		return fmt.Errorf("%w: lead time cannot be negative", domain.ErrValidation)
+	}
+
+	existing, err := s.repo.GetByName(ctx, supplier.Name)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	if err == nil && existing.ID != supplier.ID {
+		return fmt.Errorf("%w: supplier %q already exists", domain.ErrConflict, existing.Name)
+	}
+	return nil
+}
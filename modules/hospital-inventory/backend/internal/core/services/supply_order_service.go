@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hospital-inventory/internal/core/domain"
+	"hospital-inventory/internal/core/ports"
+	"strings"
+	"time"
+)
+
+type SupplyOrderService struct {
+	repo      ports.SupplyOrderRepository
+	itemRepo  ports.ItemRepository
+	suppliers ports.SupplierService
+	uow       ports.UnitOfWork
+}
+
+func NewSupplyOrderService(repo ports.SupplyOrderRepository, itemRepo ports.ItemRepository, suppliers ports.SupplierService, uow ports.UnitOfWork) ports.SupplyOrderService {
+	return &SupplyOrderService{
+		repo:      repo,
+		itemRepo:  itemRepo,
+		suppliers: suppliers,
+		uow:       uow,
+	}
+}
+
+func (s *SupplyOrderService) CreateOrder(ctx context.Context, order *domain.SupplyOrder, userID string) error {
+	supplier, err := s.suppliers.FindSupplier(ctx, order.SupplierID, order.SupplierName)
+	if err != nil {
+		return err
+	}
+	order.SupplierID = &supplier.ID
+	order.Supplier = supplier
+	order.SupplierName = supplier.Name
+
+	if len(order.Lines) == 0 {
+		return fmt.Errorf("%w: an order needs at least one line", domain.ErrValidation)
+	}
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		item, err := s.resolveLineItem(ctx, line)
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", domain.ErrValidation, i+1, err)
+		}
+		line.ItemID = item.ID
+		line.ItemName = item.Name
+		line.ReceivedQuantity = 0
+
+		switch {
+		case line.Quantity <= 0:
+			return fmt.Errorf("%w: line %d (%s): quantity must be positive", domain.ErrValidation, i+1, item.Name)
+		case line.UnitCost < 0:
+			return fmt.Errorf("%w: line %d (%s): unit cost cannot be negative", domain.ErrValidation, i+1, item.Name)
+		case line.TaxRate < 0 || line.TaxRate > 100:
+			return fmt.Errorf("%w: line %d (%s): tax rate must be between 0 and 100", domain.ErrValidation, i+1, item.Name)
+		}
+	}
+
+	order.Status = domain.OrderStatusDraft
+	order.OrderDate = time.Now()
+	order.ComputeTotals()
+	return s.uow.Do(ctx, func(repos ports.TxRepositories) error {
+		if err := repos.SupplyOrders.Create(ctx, order); err != nil {
+			return err
+		}
+		return repos.SupplyOrders.AddStatusChange(ctx, &domain.SupplyOrderStatusChange{
+			SupplyOrderID: order.ID,
+			ToStatus:      order.Status,
+			Actor:         userID,
+			Note:          "Order created",
+			ChangedAt:     order.OrderDate,
+		})
+	})
+}
+
+// resolveLineItem finds a line's item by ID, falling back to its name for rows keyed in by hand.
+func (s *SupplyOrderService) resolveLineItem(ctx context.Context, line *domain.SupplyOrderLine) (*domain.Item, error) {
+	if line.ItemID != 0 {
+		if item, err := s.itemRepo.GetByID(ctx, line.ItemID); err == nil {
+			return item, nil
+		}
+	}
+	name := strings.TrimSpace(line.ItemName)
+	if name == "" {
+		return nil, fmt.Errorf("item %d does not exist", line.ItemID)
+	}
+	item, err := s.itemRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("item %q does not exist", name)
+	}
+	return item, nil
+}
+
+func (s *SupplyOrderService) GetOrder(ctx context.Context, id uint) (*domain.SupplyOrder, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *SupplyOrderService) ListOrders(ctx context.Context) ([]domain.SupplyOrder, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *SupplyOrderService) ChangeStatus(ctx context.Context, id uint, status, userID, note string) (*domain.SupplyOrder, error) {
+	if !domain.IsOrderStatus(status) {
+		return nil, fmt.Errorf("%w: unknown order status %q", domain.ErrValidation, status)
+	}
+	// Stock only moves through goods receipts, so receiving cannot be set by hand
+	if status == domain.OrderStatusPartiallyReceived || status == domain.OrderStatusReceived {
+		return nil, fmt.Errorf("%w: record a goods receipt at /api/orders/%d/receipts to receive stock", domain.ErrConflict, id)
+	}
+
+	var order *domain.SupplyOrder
+	err := s.uow.Do(ctx, func(repos ports.TxRepositories) error {
+		var err error
+		order, err = repos.SupplyOrders.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return transitionOrder(ctx, repos.SupplyOrders, order, status, userID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *SupplyOrderService) StatusHistory(ctx context.Context, id uint) ([]domain.SupplyOrderStatusChange, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusChanges(ctx, id)
+}
+
+// transitionOrder moves the order to status, saves it and appends the audit entry.
+// The caller provides the transaction.
+func transitionOrder(ctx context.Context, orders ports.SupplyOrderRepository, order *domain.SupplyOrder, status, userID, note string) error {
+	if !order.CanTransitionTo(status) {
+		return fmt.Errorf("%w: order %s cannot move from %s to %s", domain.ErrConflict, order.PONumber(), order.Status, status)
+	}
+	change := &domain.SupplyOrderStatusChange{
+		SupplyOrderID: order.ID,
+		FromStatus:    order.Status,
+		ToStatus:      status,
+		Actor:         userID,
+		Note:          strings.TrimSpace(note),
+		ChangedAt:     time.Now(),
+	}
+	order.Status = status
+	if err := orders.Update(ctx, order); err != nil {
+		return err
+	}
+	return orders.AddStatusChange(ctx, change)
+}
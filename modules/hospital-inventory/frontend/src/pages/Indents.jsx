@@ -30,6 +30,60 @@ export default function Indents() {
         fetchIndents();
     }, []);
 
+    // Pick list being edited: { indent, line, strategy, minShelfLife, rows: [{ batch, available, quantity }] }
+    const [picking, setPicking] = useState(null);
+
+    const openPicker = async (indent, line) => {
+        try {
+            const res = await fetch(`/api/indents/${indent.id}/lines/${line.id}/picks`);
+            const data = await res.json();
+            if (!res.ok) {
+                alert(data.error || "Failed to load pick list");
+                return;
+            }
+            const held = {};
+            data.picks.forEach(p => { held[p.batch_id] = p.quantity; });
+            const rows = data.candidates.map(b => ({
+                batch: b,
+                // Stock this indent already holds is available to it again
+                available: b.quantity - b.reserved_quantity + (held[b.id] || 0),
+                quantity: held[b.id] || 0,
+            }));
+            setPicking({ indent, line, strategy: data.strategy, minShelfLife: data.min_shelf_life_days, rows });
+        } catch (error) {
+            console.error("Failed to load pick list", error);
+        }
+    };
+
+    const setPickQuantity = (batchId, value) => {
+        setPicking(prev => ({
+            ...prev,
+            rows: prev.rows.map(r => r.batch.id === batchId ? { ...r, quantity: value } : r),
+        }));
+    };
+
+    const savePicks = async () => {
+        const picks = picking.rows
+            .filter(r => Number(r.quantity) > 0)
+            .map(r => ({ batch_id: r.batch.id, quantity: Number(r.quantity) }));
+        try {
+            const res = await fetch(`/api/indents/${picking.indent.id}/lines/${picking.line.id}/picks`, {
+                method: 'PUT',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ picks })
+            });
+            if (!res.ok) {
+                const data = await res.json();
+                alert(data.error || "Failed to save pick list");
+                return;
+            }
+            setPicking(null);
+            fetchIndents();
+        } catch (error) {
+            console.error("Failed to save pick list", error);
+        }
+    };
+
     const handleStatus = async (id, status) => {
         try {
             const res = await fetch(`/api/indents/${id}/status`, {
@@ -37,9 +91,28 @@ export default function Indents() {
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify({ status })
             });
-            if (res.ok) {
-                fetchIndents();
+            if (!res.ok) {
+                const data = await res.json();
+                alert(data.error || "Failed to update status");
             }
+            fetchIndents();
+        } catch (error) {
+            console.error("Failed to update status", error);
+        }
+    };
+
+    const handleLineStatus = async (id, lineId, status) => {
+        try {
+            const res = await fetch(`/api/indents/${id}/lines/${lineId}/status`, {
+                method: 'PUT',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ status })
+            });
+            if (!res.ok) {
+                const data = await res.json();
+                alert(data.error || "Failed to update status");
+            }
+            fetchIndents();
         } catch (error) {
             console.error("Failed to update status", error);
         }
@@ -61,86 +134,157 @@ export default function Indents() {
                         <table className="w-full text-sm text-left">
                             <thead className="bg-slate-50 text-slate-500 font-medium border-b border-slate-200">
                                 <tr>
-                                    <th className="px-6 py-4">Item Name</th>
-                                    <th className="px-6 py-4">Quantity</th>
+                                    <th className="px-6 py-4">Indent / Item</th>
+                                    <th className="px-6 py-4">Requested / Approved / Sent</th>
                                     <th className="px-6 py-4">Status & Details</th>
                                     <th className="px-6 py-4 text-right">Actions</th>
                                 </tr>
                             </thead>
                             <tbody className="divide-y divide-slate-100">
                                 {indents.map((indent) => (
-                                    <tr key={indent.id} className="hover:bg-slate-50/50 transition-colors">
-                                        <td className="px-6 py-4 font-semibold text-slate-800">{indent.item_name}</td>
-                                        <td className="px-6 py-4 font-mono text-slate-600">{indent.quantity}</td>
-                                        <td className="px-6 py-4">
-                                            <div className="flex flex-col gap-1">
-                                                <span className={cn(
-                                                    "px-2 py-0.5 rounded-full text-xs font-medium w-fit",
-                                                    indent.status === 'PENDING' && "bg-amber-100 text-amber-700",
-                                                    indent.status === 'PROCESSING' && "bg-blue-100 text-blue-700",
-                                                    indent.status === 'DISPATCHED' && "bg-purple-100 text-purple-700",
-                                                    indent.status === 'FULFILLED' && "bg-green-100 text-green-700",
-                                                    indent.status === 'REJECTED' && "bg-red-100 text-red-700"
-                                                )}>
-                                                    {indent.status}
+                                    <React.Fragment key={indent.id}>
+                                        <tr className="hover:bg-slate-50/50 transition-colors">
+                                            <td className="px-6 py-4 font-semibold text-slate-800">
+                                                Indent #{indent.id}
+                                                <span className="ml-2 text-xs font-normal text-slate-500">
+                                                    {(indent.lines || []).length} item{(indent.lines || []).length === 1 ? '' : 's'}
                                                 </span>
-                                                {indent.status === 'PROCESSING' && indent.dispatch_details && (
-                                                    <div className="text-xs text-slate-500 bg-slate-50 p-2 rounded border border-slate-100 mt-1">
-                                                        <div className="font-semibold text-slate-700 mb-0.5">Suggested Batches:</div>
-                                                        {(() => {
-                                                            try {
-                                                                const details = JSON.parse(indent.dispatch_details);
-                                                                return (
-                                                                    <div className="flex flex-col gap-1 mt-1">
-                                                                        {details.map((d, idx) => (
-                                                                            <div key={idx} className="flex justify-between border-b border-slate-100 last:border-0 pb-1 last:pb-0">
-                                                                                <span>{d.batch_number} (Loc: {d.location})</span>
-                                                                                <span className="font-mono">x{d.quantity}</span>
-                                                                            </div>
-                                                                        ))}
-                                                                    </div>
-                                                                );
-                                                            } catch (e) {
-                                                                return indent.dispatch_details; // Fallback for old string format
-                                                            }
-                                                        })()}
-                                                    </div>
+                                                {indent.backorder_of_id && (
+                                                    <div className="text-xs font-normal text-amber-600">Backorder of #{indent.backorder_of_id}</div>
                                                 )}
-                                            </div>
-                                        </td>
-                                        <td className="px-6 py-4 text-right space-x-2">
-                                            {indent.status === 'PENDING' && (
-                                                <>
-                                                    <button
-                                                        onClick={() => handleStatus(indent.id, 'PROCESSING')}
-                                                        className="bg-brand-600 hover:bg-brand-700 text-white px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
-                                                    >
-                                                        Accept
-                                                    </button>
+                                            </td>
+                                            <td className="px-6 py-4 font-mono text-slate-600">
+                                                {indent.quantity} / {indent.approved_quantity || '-'} / {indent.dispatched_quantity || '-'}
+                                            </td>
+                                            <td className="px-6 py-4">
+                                                <StatusBadge status={indent.status} />
+                                            </td>
+                                            <td className="px-6 py-4 text-right space-x-2">
+                                                {indent.status === 'PENDING' && (
+                                                    <>
+                                                        <button
+                                                            onClick={() => handleStatus(indent.id, 'PROCESSING')}
+                                                            className="bg-brand-600 hover:bg-brand-700 text-white px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                        >
+                                                            Accept All
+                                                        </button>
+                                                        <button
+                                                            onClick={() => handleStatus(indent.id, 'REJECTED')}
+                                                            className="bg-red-50 hover:bg-red-100 text-red-600 px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                        >
+                                                            Reject All
+                                                        </button>
+                                                    </>
+                                                )}
+                                                {indent.status === 'PROCESSING' && (
                                                     <button
-                                                        onClick={() => handleStatus(indent.id, 'REJECTED')}
-                                                        className="bg-red-50 hover:bg-red-100 text-red-600 px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                        onClick={() => handleStatus(indent.id, 'DISPATCHED')}
+                                                        className="bg-purple-600 hover:bg-purple-700 text-white px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
                                                     >
-                                                        Reject
+                                                        Dispatch All
                                                     </button>
-                                                </>
-                                            )}
-                                            {indent.status === 'PROCESSING' && (
-                                                <button
-                                                    onClick={() => handleStatus(indent.id, 'DISPATCHED')}
-                                                    className="bg-purple-600 hover:bg-purple-700 text-white px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
-                                                >
-                                                    Dispatch Stock
-                                                </button>
-                                            )}
-                                            {indent.status === 'DISPATCHED' && (
-                                                <span className="text-xs text-slate-400 italic">Awaiting Confirmation</span>
-                                            )}
-                                            {indent.status === 'FULFILLED' && (
-                                                <span className="text-xs text-green-600 font-medium">Completed</span>
-                                            )}
-                                        </td>
-                                    </tr>
+                                                )}
+                                                {(indent.status === 'DISPATCHED' || indent.status === 'PARTIALLY_DISPATCHED') && (
+                                                    <span className="text-xs text-slate-400 italic">Awaiting Confirmation</span>
+                                                )}
+                                                {indent.status === 'FULFILLED' && (
+                                                    <span className="text-xs text-green-600 font-medium">Completed</span>
+                                                )}
+                                            </td>
+                                        </tr>
+                                        {(indent.lines || []).map((line) => (
+                                            <React.Fragment key={line.id}>
+                                                <tr className="bg-slate-50/40 text-xs">
+                                                    <td className="pl-10 pr-6 py-2 text-slate-700">{line.item_name}</td>
+                                                    <td className="px-6 py-2 font-mono text-slate-500">
+                                                        {line.quantity} / {line.approved_quantity || '-'} / {line.dispatched_quantity || '-'}
+                                                    </td>
+                                                    <td className="px-6 py-2">
+                                                        <div className="flex flex-col gap-1">
+                                                            <StatusBadge status={line.status} />
+                                                            {line.status === 'PROCESSING' && line.dispatch_details && (
+                                                                <DispatchDetails details={line.dispatch_details} />
+                                                            )}
+                                                        </div>
+                                                    </td>
+                                                    <td className="px-6 py-2 text-right space-x-2">
+                                                        {line.status === 'PENDING' && (
+                                                            <button
+                                                                onClick={() => handleLineStatus(indent.id, line.id, 'PROCESSING')}
+                                                                className="bg-slate-100 hover:bg-slate-200 text-slate-700 px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                            >
+                                                                Accept
+                                                            </button>
+                                                        )}
+                                                        {line.status === 'PROCESSING' && (
+                                                            <button
+                                                                onClick={() => openPicker(indent, line)}
+                                                                className="bg-slate-100 hover:bg-slate-200 text-slate-700 px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                            >
+                                                                Edit Picks
+                                                            </button>
+                                                        )}
+                                                        {(line.status === 'PENDING' || line.status === 'PROCESSING') && (
+                                                            <button
+                                                                onClick={() => handleLineStatus(indent.id, line.id, 'REJECTED')}
+                                                                className="bg-red-50 hover:bg-red-100 text-red-600 px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                            >
+                                                                Reject
+                                                            </button>
+                                                        )}
+                                                    </td>
+                                                </tr>
+                                                {picking?.line.id === line.id && (
+                                                    <tr className="bg-slate-50">
+                                                        <td colSpan={4} className="px-6 py-4">
+                                                            <div className="font-semibold text-slate-700 mb-2">
+                                                                Pick list for {line.quantity} x {line.item_name}
+                                                                <span className="ml-2 font-normal text-slate-500">
+                                                                    ({picking.rows.reduce((sum, r) => sum + (Number(r.quantity) || 0), 0)} picked)
+                                                                </span>
+                                                            </div>
+                                                            <p className="text-xs text-slate-500 mb-2">
+                                                                {picking.strategy} order, batches need {picking.minShelfLife} days of shelf life left
+                                                            </p>
+                                                            {picking.rows.length === 0 && <p className="text-slate-500 text-xs">No batches in stock with enough shelf life.</p>}
+                                                            <div className="flex flex-col gap-1">
+                                                                {picking.rows.map(r => (
+                                                                    <div key={r.batch.id} className="flex items-center justify-between gap-4 text-xs">
+                                                                        <span className="flex-1">
+                                                                            {r.batch.batch_number} (Loc: {r.batch.location || '-'}, Exp: {r.batch.expiry_date?.slice(0, 10)})
+                                                                        </span>
+                                                                        <span className="text-slate-500">{r.available} available</span>
+                                                                        <input
+                                                                            type="number"
+                                                                            min="0"
+                                                                            max={r.available}
+                                                                            value={r.quantity}
+                                                                            onChange={e => setPickQuantity(r.batch.id, e.target.value)}
+                                                                            className="w-20 border border-slate-200 rounded px-2 py-1 font-mono"
+                                                                        />
+                                                                    </div>
+                                                                ))}
+                                                            </div>
+                                                            <div className="flex justify-end gap-2 mt-3">
+                                                                <button
+                                                                    onClick={() => setPicking(null)}
+                                                                    className="bg-white border border-slate-200 hover:bg-slate-100 text-slate-600 px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                                >
+                                                                    Cancel
+                                                                </button>
+                                                                <button
+                                                                    onClick={savePicks}
+                                                                    className="bg-brand-600 hover:bg-brand-700 text-white px-3 py-1.5 rounded-md text-xs font-medium transition-colors"
+                                                                >
+                                                                    Save Picks
+                                                                </button>
+                                                            </div>
+                                                        </td>
+                                                    </tr>
+                                                )}
+                                            </React.Fragment>
+                                        ))}
+                                    </React.Fragment>
                                 ))}
                             </tbody>
                         </table>
@@ -150,3 +294,43 @@ export default function Indents() {
         </div>
     );
 }
+
+function StatusBadge({ status }) {
+    return (
+        <span className={cn(
+            "px-2 py-0.5 rounded-full text-xs font-medium w-fit",
+            status === 'PENDING' && "bg-amber-100 text-amber-700",
+            status === 'PROCESSING' && "bg-blue-100 text-blue-700",
+            status === 'DISPATCHED' && "bg-purple-100 text-purple-700",
+            status === 'PARTIALLY_DISPATCHED' && "bg-orange-100 text-orange-700",
+            status === 'FULFILLED' && "bg-green-100 text-green-700",
+            status === 'REJECTED' && "bg-red-100 text-red-700"
+        )}>
+            {status}
+        </span>
+    );
+}
+
+function DispatchDetails({ details }) {
+    let parsed;
+    try {
+        parsed = JSON.parse(details);
+    } catch (e) {
+        parsed = null;
+    }
+    return (
+        <div className="text-xs text-slate-500 bg-slate-50 p-2 rounded border border-slate-100 mt-1">
+            <div className="font-semibold text-slate-700 mb-0.5">Suggested Batches:</div>
+            {Array.isArray(parsed) ? (
+                <div className="flex flex-col gap-1 mt-1">
+                    {parsed.map((d, idx) => (
+                        <div key={idx} className="flex justify-between border-b border-slate-100 last:border-0 pb-1 last:pb-0">
+                            <span>{d.batch_number} (Loc: {d.location})</span>
+                            <span className="font-mono">x{d.quantity}</span>
+                        </div>
+                    ))}
+                </div>
+            ) : details /* Fallback for old string format */}
+        </div>
+    );
+}
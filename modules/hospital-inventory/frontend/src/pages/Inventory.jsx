@@ -143,7 +143,8 @@ export default function Inventory() {
         name: '',
         description: '',
         threshold: 10,
-        unit: 'Pack'
+        unit: 'Pack',
+        min_shelf_life_days: ''
     });
 
     // Audit Log State
@@ -156,7 +157,9 @@ export default function Inventory() {
                 name: editItemData.name,
                 description: editItemData.description,
                 threshold: parseInt(editItemData.threshold),
-                unit: editItemData.unit
+                unit: editItemData.unit,
+                // Blank falls back to the category's minimum shelf life
+                min_shelf_life_days: editItemData.min_shelf_life_days === '' ? -1 : parseInt(editItemData.min_shelf_life_days)
             };
 
             const response = await fetch(`/api/items/${selectedItemToEdit.id}`, {
@@ -740,6 +743,17 @@ export default function Inventory() {
                                         onChange={(e) => setEditItemData({ ...editItemData, threshold: e.target.value })}
                                     />
                                 </div>
+                                <div>
+                                    <label className="block text-sm font-medium text-slate-700 mb-1">Min Shelf Life (days)</label>
+                                    <input
+                                        type="number"
+                                        min="0"
+                                        placeholder={`Category default (${selectedItemToEdit?.category?.min_shelf_life_days || 0})`}
+                                        className="w-full px-3 py-2 border border-slate-200 rounded-lg text-sm focus:outline-none focus:ring-2 focus:ring-brand-500"
+                                        value={editItemData.min_shelf_life_days}
+                                        onChange={(e) => setEditItemData({ ...editItemData, min_shelf_life_days: e.target.value })}
+                                    />
+                                </div>
                             </div>
 
                             <div className="pt-2 flex justify-end gap-3">
@@ -1042,7 +1056,8 @@ export default function Inventory() {
                                                                 name: item.name,
                                                                 description: item.description,
                                                                 threshold: item.threshold,
-                                                                unit: item.unit
+                                                                unit: item.unit,
+                                                                min_shelf_life_days: item.min_shelf_life_days ?? ''
                                                             });
                                                             setIsEditItemOpen(true);
                                                             setRowMenuOpenId(null);
@@ -1,7 +1,22 @@
 import React, { useState, useEffect } from 'react';
 import { Plus, Download, Printer, Save, Trash2, Edit2, CheckCircle, Clock, X, AlertTriangle, FileText } from 'lucide-react';
-import { jsPDF } from 'jspdf';
-import autoTable from 'jspdf-autotable';
+
+// Manual moves offered per status. Receiving happens through goods receipts.
+const NEXT_ACTIONS = {
+    'Draft': [{ status: 'Approved', label: 'Approve' }, { status: 'Cancelled', label: 'Cancel' }],
+    'Approved': [{ status: 'Sent', label: 'Mark Sent' }, { status: 'Cancelled', label: 'Cancel' }],
+    'Sent': [{ status: 'Cancelled', label: 'Cancel' }],
+    'Partially Received': [{ status: 'Cancelled', label: 'Close' }],
+};
+
+const STATUS_STYLES = {
+    'Draft': 'bg-slate-100 text-slate-700',
+    'Approved': 'bg-indigo-100 text-indigo-700',
+    'Sent': 'bg-amber-100 text-amber-700',
+    'Partially Received': 'bg-sky-100 text-sky-700',
+    'Received': 'bg-green-100 text-green-700',
+    'Cancelled': 'bg-red-100 text-red-700',
+};
 
 const Orders = () => {
     const [orders, setOrders] = useState([]);
@@ -57,52 +72,35 @@ const Orders = () => {
         }
     };
 
-    const generateSuggestions = (items) => {
-        const today = new Date();
-        const warningDate = new Date();
-        warningDate.setDate(today.getDate() + 30);
-
-        const suggestions = [];
-
-        items.forEach(item => {
-            let reason = '';
-
-            // Check Stock
-            if (item.total_quantity <= (item.threshold || 10)) {
-                reason = `Low Stock (${item.total_quantity} left)`;
-            }
-            // Check Expiry (if needed - currently using item aggregated data, simplified)
-            // Ideally we'd iterate batches here if available in item data
-            else if (item.batches && item.batches.length > 0) {
-                const expiringInfo = item.batches.find(b => new Date(b.expiry_date) <= warningDate);
-                if (expiringInfo) {
-                    reason = `Batch Expiring`;
-                }
-            }
-
-            if (reason) {
-                suggestions.push({
-                    item_id: item.id,
-                    item_name: item.name,
-                    quantity: (item.restock_level || 50), // Default restock Qty
-                    unit_cost: item.price || 0,
-                    total: (item.restock_level || 50) * (item.price || 0),
-                    reason: reason
-                });
-            }
-        });
-
-        return suggestions;
+    // Suggestions come from consumption history on the backend, grouped by supplier.
+    // The first group pre-fills the form; other suppliers need their own order.
+    const fetchSuggestions = async () => {
+        try {
+            const res = await fetch('http://localhost:8080/api/orders/suggestions');
+            const data = await res.json();
+            return data.suppliers || [];
+        } catch (err) {
+            console.error("Failed to fetch suggestions", err);
+            return [];
+        }
     };
 
     const handleOpenCreate = async () => {
-        const items = await fetchInventory();
+        fetchInventory();
         fetchKnowledgeBase(); // Fetch KB when modal opens
-        const suggested = generateSuggestions(items);
+        const groups = await fetchSuggestions();
+        const first = groups.find(g => g.supplier_id) || groups[0];
 
         setFormData({
-            supplier_name: '',
-            items: suggested
+            supplier_name: first && first.supplier_id ? first.supplier_name : '',
+            items: (first ? first.items : []).map(s => ({
+                item_id: s.item_id,
+                item_name: s.item_name,
+                quantity: s.quantity,
+                unit_cost: s.unit_cost,
+                total: s.quantity * s.unit_cost,
+                reason: s.reason
+            }))
         });
         setShowModal(true);
     };
@@ -139,135 +137,9 @@ const Orders = () => {
         return formData.items.reduce((sum, item) => sum + (item.total || 0), 0);
     };
 
-    const generatePDF = (orderData, id) => {
-
-        try {
-            const doc = new jsPDF();
-
-
-            // Brand Colors
-            const primaryColor = [41, 128, 185]; // A nice blue
-            const slateColor = [52, 73, 94];
-
-            // 1. Header Section
-            // Logo placeholder / Brand Name
-            doc.setFillColor(...primaryColor);
-            doc.rect(0, 0, 210, 40, 'F'); // Top banner
-
-            doc.setTextColor(255, 255, 255);
-            doc.setFontSize(26);
-            doc.setFont("helvetica", "bold");
-            doc.text("PURCHASE ORDER", 14, 25);
-
-            doc.setFontSize(10);
-            doc.setFont("helvetica", "normal");
-            doc.text("spamMED Hospital Inventory", 200, 20, { align: "right" });
-            doc.text("123 Health Avenue, MedCity", 200, 25, { align: "right" });
-
-            // 2. Order Details
-            doc.setTextColor(...slateColor);
-            doc.setFontSize(10);
-
-            const detailStartY = 55;
-            // Left Side: Order Info
-            doc.setFont("helvetica", "bold");
-            doc.text("ORDER DETAILS", 14, detailStartY);
-            doc.setDrawColor(200, 200, 200);
-            doc.line(14, detailStartY + 2, 80, detailStartY + 2); // Underline
-
-            doc.setFont("helvetica", "normal");
-            doc.text(`Order ID: #${id}`, 14, detailStartY + 10);
-            doc.text(`Date: ${new Date().toLocaleDateString()}`, 14, detailStartY + 16);
-            doc.text(`Status: ${orderData.status}`, 14, detailStartY + 22);
-
-            // Right Side: Supplier Info
-            doc.setFont("helvetica", "bold");
-            doc.text("SUPPLIER", 120, detailStartY);
-            doc.line(120, detailStartY + 2, 180, detailStartY + 2); // Underline
-
-            doc.setFont("helvetica", "normal");
-            doc.text(orderData.supplier_name, 120, detailStartY + 10);
-            // Placeholder address for now
-            doc.text("Attn: Sales Department", 120, detailStartY + 16);
-
-            // 3. Table
-            const tableColumn = ["Item Description", "Quantity", "Unit Cost", "Total"];
-            const tableRows = [];
-
-            orderData.items.forEach(item => {
-                const orderItem = [
-                    item.item_name,
-                    item.quantity,
-                    `Rs. ${item.unit_cost}`,
-                    `Rs. ${item.total.toFixed(2)}`
-                ];
-                tableRows.push(orderItem);
-            });
-
-
-            autoTable(doc, {
-                head: [tableColumn],
-                body: tableRows,
-                startY: 90,
-                theme: 'grid',
-                headStyles: {
-                    fillColor: primaryColor,
-                    textColor: [255, 255, 255],
-                    fontStyle: 'bold',
-                    halign: 'center'
-                },
-                columnStyles: {
-                    0: { halign: 'left' },
-                    1: { halign: 'center' },
-                    2: { halign: 'right' },
-                    3: { halign: 'right', fontStyle: 'bold' }
-                },
-                alternateRowStyles: {
-                    fillColor: [245, 247, 250]
-                },
-                styles: {
-                    font: 'helvetica',
-                    fontSize: 10,
-                    cellPadding: 6
-                }
-            });
-
-            // 4. Grand Total & Signatures
-            const finalY = (doc.lastAutoTable?.finalY || 90) + 15;
-
-            // Box for Total
-            doc.setFillColor(240, 240, 240);
-            doc.rect(130, finalY - 5, 60, 20, 'F');
-
-            doc.setFontSize(12);
-            doc.setTextColor(...slateColor);
-            doc.setFont("helvetica", "bold");
-            doc.text(`Grand Total:`, 135, finalY + 8);
-            doc.setTextColor(...primaryColor);
-            doc.text(`Rs. ${orderData.total_cost.toFixed(2)}`, 185, finalY + 8, { align: "right" });
-
-            // Signature Line
-            const signY = finalY + 40;
-            doc.setDrawColor(0, 0, 0);
-            doc.line(14, signY, 80, signY);
-            doc.setFontSize(8);
-            doc.setTextColor(...slateColor);
-            doc.setFont("helvetica", "normal");
-            doc.text("Authorized Signature", 14, signY + 5);
-
-            // 5. Footer
-            const pageHeight = doc.internal.pageSize.height;
-            doc.setFillColor(245, 245, 245);
-            doc.rect(0, pageHeight - 20, 210, 20, 'F');
-            doc.text("Generated by spamMED Hospital Inventory System | Contact: support@spammed.com", 105, pageHeight - 10, { align: "center" });
-
-            // Save PDF
-            doc.save(`PO_${id}_${orderData.supplier_name.replace(/\s+/g, '_')}.pdf`);
-
-        } catch (e) {
-            console.error("PDF Generation Error (Detailed):", e);
-            alert(`PDF Generation Failed: ${e.message}`);
-        }
+    // Purchase orders are rendered by the backend so every copy is the same document
+    const downloadPDF = (id) => {
+        window.open(`http://localhost:8080/api/orders/${id}/pdf`, '_blank');
     };
 
     const handleSubmit = async (e) => {
@@ -276,8 +148,13 @@ const Orders = () => {
         // Prepare Submission
         const payload = {
             supplier_name: formData.supplier_name,
-            items: JSON.stringify(formData.items),
-            total_cost: calculateGrandTotal()
+            // Totals are computed by the server from the lines
+            lines: formData.items.map(item => ({
+                item_id: item.isKeyed ? 0 : item.item_id,
+                item_name: item.item_name,
+                quantity: parseInt(item.quantity) || 0,
+                unit_cost: parseFloat(item.unit_cost) || 0
+            }))
         };
 
         try {
@@ -292,10 +169,7 @@ const Orders = () => {
                 setShowModal(false);
                 fetchOrders();
 
-                // Trigger PDF
-                // Transform payload items back to array for PDF gen since state is cleaner
-                const pdfData = { ...createdOrder, items: formData.items };
-                generatePDF(pdfData, createdOrder.id);
+                downloadPDF(createdOrder.id);
 
                 alert("Order Created & PDF Downloaded!");
             }
@@ -309,10 +183,21 @@ const Orders = () => {
         fetchOrders();
     }, []);
 
+    const changeStatus = async (id, status) => {
+        const res = await fetch(`http://localhost:8080/api/orders/${id}/status`, {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ status })
+        });
+        if (!res.ok) {
+            const data = await res.json();
+            alert(data.error || "Failed to update status");
+        }
+        fetchOrders();
+    };
+
     const StatusBadge = ({ status }) => (
-        <span className={`px-2 py-1 rounded-full text-xs font-medium ${status === 'Pending' ? 'bg-amber-100 text-amber-700' :
-            status === 'Received' ? 'bg-green-100 text-green-700' : 'bg-red-100 text-red-700'
-            }`}>
+        <span className={`px-2 py-1 rounded-full text-xs font-medium ${STATUS_STYLES[status] || 'bg-slate-100 text-slate-700'}`}>
             {status}
         </span>
     );
@@ -350,7 +235,7 @@ const Orders = () => {
                             <tr><td colSpan="7" className="px-6 py-8 text-center text-slate-500">No orders found.</td></tr>
                         ) : (
                             orders.map(order => {
-                                const itemCount = JSON.parse(order.items || "[]").length;
+                                const itemCount = (order.lines || []).length;
                                 return (
                                     <tr key={order.id} className="hover:bg-slate-50/50">
                                         <td className="px-6 py-4 font-mono text-slate-600">#{order.id}</td>
@@ -359,22 +244,22 @@ const Orders = () => {
                                         <td className="px-6 py-4 text-slate-600">{itemCount} items</td>
                                         <td className="px-6 py-4 font-mono font-medium text-slate-800">₹{order.total_cost.toFixed(2)}</td>
                                         <td className="px-6 py-4"><StatusBadge status={order.status} /></td>
-                                        <td className="px-6 py-4 text-right">
-                                            {order.status === 'Pending' && (
+                                        <td className="px-6 py-4 text-right space-x-3">
+                                            <button
+                                                className="text-slate-500 hover:text-slate-800 font-medium text-xs inline-flex items-center gap-1"
+                                                onClick={() => downloadPDF(order.id)}
+                                            >
+                                                <Download size={14} /> PDF
+                                            </button>
+                                            {(NEXT_ACTIONS[order.status] || []).map(action => (
                                                 <button
-                                                    className="text-brand-600 hover:text-brand-800 font-medium text-xs"
-                                                    onClick={async () => {
-                                                        await fetch(`http://localhost:8080/api/orders/${order.id}/status`, {
-                                                            method: 'PUT',
-                                                            headers: { 'Content-Type': 'application/json' },
-                                                            body: JSON.stringify({ status: 'Received' })
-                                                        });
-                                                        fetchOrders();
-                                                    }}
+                                                    key={action.status}
+                                                    className={`font-medium text-xs ${action.status === 'Cancelled' ? 'text-red-600 hover:text-red-800' : 'text-brand-600 hover:text-brand-800'}`}
+                                                    onClick={() => changeStatus(order.id, action.status)}
                                                 >
-                                                    Mark Received
+                                                    {action.label}
                                                 </button>
-                                            )}
+                                            ))}
                                         </td>
                                     </tr>
                                 )
@@ -391,7 +276,7 @@ const Orders = () => {
                         <div className="px-6 py-4 border-b border-slate-100 flex justify-between items-center bg-slate-50">
                             <div>
                                 <h3 className="font-semibold text-lg text-slate-800">New Supply Order</h3>
-                                <p className="text-xs text-slate-500">Auto-filled based on low stock & expiry</p>
+                                <p className="text-xs text-slate-500">Auto-filled from consumption history and lead times</p>
                             </div>
                             <button onClick={() => setShowModal(false)} className="text-slate-400 hover:text-slate-600">
                                 <X size={24} />
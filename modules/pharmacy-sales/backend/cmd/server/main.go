@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -23,11 +25,12 @@ func main() {
 	repo.SeedKnowledge()
 
 	// 3. Initialize Services
-	inventoryService := services.NewInventoryService(repo)
-	billingService := services.NewBillingService(repo, repo)
+	billingService := services.NewBillingService(repo, repo, repo, repo, aliasPromotionThreshold())
+	inventoryService := services.NewInventoryService(repo, billingService)
+	knowledgeService := services.NewKnowledgeService(repo, repo, billingService)
 
 	// 4. Initialize Handlers
-	h := handlers.NewHTTPHandler(inventoryService, billingService)
+	h := handlers.NewHTTPHandler(inventoryService, billingService, knowledgeService)
 
 	// 5. Setup Router
 	r := mux.NewRouter()
@@ -40,11 +43,19 @@ func main() {
 	api.HandleFunc("/items/knowledge-base", h.HandleKnowledgeBase).Methods("GET", "OPTIONS")
 	api.HandleFunc("/items/{id}", h.HandleItemDetail).Methods("PUT", "DELETE", "OPTIONS")
 
+	// Alias Knowledge Base
+	api.HandleFunc("/aliases", h.HandleAliases).Methods("GET", "POST", "OPTIONS")
+	api.HandleFunc("/aliases/canonical/{name}", h.HandleCanonicalName).Methods("PUT", "DELETE", "OPTIONS")
+	api.HandleFunc("/aliases/{id}", h.HandleAliasDetail).Methods("PUT", "DELETE", "OPTIONS")
+	api.HandleFunc("/feedback", h.HandleFeedback).Methods("POST", "OPTIONS")
+
 	// Batches
 	api.HandleFunc("/batches", h.HandleBatches).Methods("POST", "OPTIONS")
 	api.HandleFunc("/batches/{id}", h.HandleBatchDetail).Methods("PUT", "DELETE", "OPTIONS")
 
 	// Sales
+	api.HandleFunc("/sales", h.HandleSales).Methods("GET", "POST", "OPTIONS")
+	api.HandleFunc("/sales/{id}", h.HandleSaleDetail).Methods("GET", "OPTIONS")
 	r.HandleFunc("/process-sale", h.HandleProcessSale).Methods("POST", "OPTIONS")
 	r.HandleFunc("/receive-indent", h.HandleReceiveIndent).Methods("POST", "OPTIONS")
 
@@ -54,3 +65,15 @@ func main() {
 	fmt.Println("Starting Modular Server on :8081...")
 	log.Fatal(http.ListenAndServe(":8081", r))
 }
+
+// aliasPromotionThreshold reads ALIAS_PROMOTION_THRESHOLD, the number of identical
+// corrections after which a captured name becomes an alias. Defaults to 3.
+func aliasPromotionThreshold() int {
+	if v := os.Getenv("ALIAS_PROMOTION_THRESHOLD"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		log.Printf("Ignoring invalid ALIAS_PROMOTION_THRESHOLD %q", v)
+	}
+	return 3
+}
@@ -4,8 +4,10 @@ import (
 	"billing-module/internal/core/domain"
 	"billing-module/internal/core/ports"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -13,12 +15,14 @@ import (
 type HTTPHandler struct {
 	inventoryService ports.InventoryService
 	billingService   ports.BillingService
+	knowledgeService ports.KnowledgeService
 }
 
-func NewHTTPHandler(inventoryService ports.InventoryService, billingService ports.BillingService) *HTTPHandler {
+func NewHTTPHandler(inventoryService ports.InventoryService, billingService ports.BillingService, knowledgeService ports.KnowledgeService) *HTTPHandler {
 	return &HTTPHandler{
 		inventoryService: inventoryService,
 		billingService:   billingService,
+		knowledgeService: knowledgeService,
 	}
 }
 
@@ -153,6 +157,88 @@ func (h *HTTPHandler) HandleProcessSale(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(sales)
 }
 
+type CommitSaleRequest struct {
+	CustomerName string            `json:"customer_name"`
+	Items        []domain.SaleItem `json:"items"`
+}
+
+func (h *HTTPHandler) HandleSales(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == "GET" {
+		// Optional date range (YYYY-MM-DD, inclusive) for reconciling the cash drawer
+		var from, to time.Time
+		if v := r.URL.Query().Get("from"); v != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", v, time.Local)
+			if err != nil {
+				http.Error(w, "Invalid from date, use YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			from = parsed
+		}
+		if v := r.URL.Query().Get("to"); v != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", v, time.Local)
+			if err != nil {
+				http.Error(w, "Invalid to date, use YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			to = parsed.AddDate(0, 0, 1)
+		}
+
+		invoices, err := h.billingService.ListInvoices(from, to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(invoices)
+	} else if r.Method == "POST" {
+		var req CommitSaleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		invoice, err := h.billingService.CommitSale(req.CustomerName, req.Items)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrInvalidSale):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, domain.ErrInsufficientStock):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(invoice)
+	}
+}
+
+func (h *HTTPHandler) HandleSaleDetail(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	vars := mux.Vars(r)
+	if r.Method == "GET" {
+		invoice, err := h.billingService.GetInvoice(vars["id"])
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(invoice)
+	}
+}
+
 func (h *HTTPHandler) HandleReceiveIndent(w http.ResponseWriter, r *http.Request) {
 	enableCors(&w)
 	if r.Method == "OPTIONS" {
@@ -194,3 +280,136 @@ func (h *HTTPHandler) HandleKnowledgeBase(w http.ResponseWriter, r *http.Request
 		json.NewEncoder(w).Encode(items)
 	}
 }
+
+// Alias Handlers
+
+type CorrectionRequest struct {
+	CapturedName string `json:"captured_name"`
+	ItemName     string `json:"item_name"`
+}
+
+// HandleFeedback records a pharmacist's correction of a match.
+func (h *HTTPHandler) HandleFeedback(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == "POST" {
+		var req CorrectionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		feedback, err := h.billingService.RecordCorrection(req.CapturedName, req.ItemName)
+		if err != nil {
+			writeKnowledgeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(feedback)
+	}
+}
+
+func (h *HTTPHandler) HandleAliases(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == "GET" {
+		aliases, err := h.knowledgeService.ListAliases()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(aliases)
+	} else if r.Method == "POST" {
+		var alias domain.Alias
+		if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id, err := h.knowledgeService.AddAlias(alias)
+		if err != nil {
+			writeKnowledgeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]int64{"id": id})
+	}
+}
+
+func (h *HTTPHandler) HandleAliasDetail(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	vars := mux.Vars(r)
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == "PUT" {
+		var alias domain.Alias
+		if err := json.NewDecoder(r.Body).Decode(&alias); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.knowledgeService.UpdateAlias(vars["id"], alias); err != nil {
+			writeKnowledgeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	} else if r.Method == "DELETE" {
+		if err := h.knowledgeService.DeleteAlias(vars["id"]); err != nil {
+			writeKnowledgeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// HandleCanonicalName renames (PUT {"canonical_name": "..."}) or deletes all aliases of a canonical name.
+func (h *HTTPHandler) HandleCanonicalName(w http.ResponseWriter, r *http.Request) {
+	enableCors(&w)
+	vars := mux.Vars(r)
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == "PUT" {
+		var req struct {
+			CanonicalName string `json:"canonical_name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.knowledgeService.RenameCanonical(vars["name"], req.CanonicalName); err != nil {
+			writeKnowledgeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	} else if r.Method == "DELETE" {
+		if err := h.knowledgeService.DeleteCanonical(vars["name"]); err != nil {
+			writeKnowledgeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func writeKnowledgeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAlias):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrDuplicate):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrUnknownItem):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
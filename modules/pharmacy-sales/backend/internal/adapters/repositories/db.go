@@ -34,7 +34,8 @@ func createTables(db *sql.DB) {
 		unit TEXT DEFAULT 'Unit',
 		price REAL NOT NULL,
 		category_id INTEGER,
-		restock_level INTEGER
+		restock_level INTEGER,
+		pack_size INTEGER DEFAULT 0
 	);`
 
 	queryBatches := `
@@ -61,6 +62,53 @@ func createTables(db *sql.DB) {
 		UNIQUE(canonical_name, alias)
 	);`
 
+	queryInvoices := `
+	CREATE TABLE IF NOT EXISTS sales_invoices (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		invoice_number TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL,
+		customer_name TEXT,
+		subtotal REAL DEFAULT 0,
+		discount_total REAL DEFAULT 0,
+		tax_total REAL DEFAULT 0,
+		grand_total REAL DEFAULT 0
+	);`
+
+	queryInvoiceLines := `
+	CREATE TABLE IF NOT EXISTS sales_invoice_lines (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		invoice_id INTEGER NOT NULL REFERENCES sales_invoices(id),
+		item_id INTEGER NOT NULL,
+		item_name TEXT NOT NULL,
+		batch_id INTEGER NOT NULL,
+		batch_number TEXT NOT NULL,
+		expiry_date DATETIME,
+		mrp REAL DEFAULT 0,
+		quantity INTEGER NOT NULL,
+		discount REAL DEFAULT 0,
+		tax_rate REAL DEFAULT 0,
+		tax_amount REAL DEFAULT 0,
+		line_total REAL DEFAULT 0
+	);`
+
+	queryFeedback := `
+	CREATE TABLE IF NOT EXISTS match_feedback (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		captured_text TEXT NOT NULL,
+		canonical_name TEXT NOT NULL,
+		confirmations INTEGER NOT NULL DEFAULT 0,
+		promoted INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		UNIQUE(captured_text, canonical_name)
+	);`
+
+	queryReceivedIndents := `
+	CREATE TABLE IF NOT EXISTS received_indents (
+		indent_id INTEGER PRIMARY KEY,
+		received_at DATETIME NOT NULL
+	);`
+
 	if _, err := db.Exec(queryItems); err != nil {
 		log.Fatal("Failed to create items table:", err)
 	}
@@ -70,4 +118,41 @@ func createTables(db *sql.DB) {
 	if _, err := db.Exec(queryAliases); err != nil {
 		log.Fatal("Failed to create medicine_aliases table:", err)
 	}
+	// The items table is shared with the hospital module and may predate these columns
+	addColumnIfMissing(db, "items", "pack_size", "INTEGER DEFAULT 0")
+
+	if _, err := db.Exec(queryInvoices); err != nil {
+		log.Fatal("Failed to create sales_invoices table:", err)
+	}
+	if _, err := db.Exec(queryInvoiceLines); err != nil {
+		log.Fatal("Failed to create sales_invoice_lines table:", err)
+	}
+	if _, err := db.Exec(queryFeedback); err != nil {
+		log.Fatal("Failed to create match_feedback table:", err)
+	}
+	if _, err := db.Exec(queryReceivedIndents); err != nil {
+		log.Fatal("Failed to create received_indents table:", err)
+	}
+}
+
+func addColumnIfMissing(db *sql.DB, table, column, definition string) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		log.Fatalf("Failed to inspect %s table: %v", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			log.Fatalf("Failed to inspect %s table: %v", table, err)
+		}
+		if name == column {
+			return
+		}
+	}
+
+	if _, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition); err != nil {
+		log.Fatalf("Failed to add %s.%s column: %v", table, column, err)
+	}
 }
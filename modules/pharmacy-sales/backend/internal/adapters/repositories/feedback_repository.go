@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"billing-module/internal/core/domain"
+	"time"
+)
+
+// --- FeedbackRepository Implementation ---
+
+// RecordFeedback adds one confirmation for the (captured text, canonical name) pair.
+func (r *SQLiteRepository) RecordFeedback(capturedText, canonicalName string) (*domain.MatchFeedback, error) {
+	now := time.Now()
+	_, err := r.DB.Exec(`
+		INSERT INTO match_feedback (captured_text, canonical_name, confirmations, created_at, updated_at)
+		VALUES (?, ?, 1, ?, ?)
+		ON CONFLICT(captured_text, canonical_name) DO UPDATE SET confirmations = confirmations + 1, updated_at = excluded.updated_at
+	`, capturedText, canonicalName, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var f domain.MatchFeedback
+	err = r.DB.QueryRow("SELECT id, captured_text, canonical_name, confirmations, promoted FROM match_feedback WHERE captured_text = ? AND canonical_name = ?",
+		capturedText, canonicalName).Scan(&f.ID, &f.CapturedText, &f.CanonicalName, &f.Confirmations, &f.Promoted)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *SQLiteRepository) MarkFeedbackPromoted(id int) error {
+	_, err := r.DB.Exec("UPDATE match_feedback SET promoted = 1, updated_at = ? WHERE id = ?", time.Now(), id)
+	return err
+}
+
+// GetFeedbackCounts returns confirmations keyed by captured text, then canonical name.
+func (r *SQLiteRepository) GetFeedbackCounts() (map[string]map[string]int, error) {
+	rows, err := r.DB.Query("SELECT captured_text, canonical_name, confirmations FROM match_feedback")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]int)
+	for rows.Next() {
+		var captured, canonical string
+		var confirmations int
+		if err := rows.Scan(&captured, &canonical, &confirmations); err != nil {
+			return nil, err
+		}
+		if result[captured] == nil {
+			result[captured] = make(map[string]int)
+		}
+		result[captured][canonical] = confirmations
+	}
+	return result, rows.Err()
+}
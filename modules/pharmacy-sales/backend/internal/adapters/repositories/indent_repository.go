@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"billing-module/internal/core/domain"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ReceiveIndent books the batches of every indent line, or none of them. The indent is recorded
+// in received_indents in the same transaction, so a retry after a failure cannot add stock twice.
+func (r *SQLiteRepository) ReceiveIndent(indentID int, lines []domain.IndentReceiptLine) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	res, err := tx.Exec("INSERT OR IGNORE INTO received_indents (indent_id, received_at) VALUES (?, ?)", indentID, now)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("%w: indent %d was received before", domain.ErrDuplicate, indentID)
+	}
+
+	for _, line := range lines {
+		itemID, err := findOrCreateItem(tx, line.ItemName, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", line.ItemName, err)
+		}
+		for _, b := range line.Batches {
+			_, err := tx.Exec("INSERT INTO pharmacy_batches (item_id, batch_number, expiry_date, quantity, mrp, location, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				itemID, b.BatchNumber, b.Expiry.Format(time.RFC3339), b.Quantity, b.MRP, b.Location, now, now)
+			if err != nil {
+				return fmt.Errorf("%s: failed to add batch %s: %w", line.ItemName, b.BatchNumber, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// findOrCreateItem looks the item up by name in the shared items table, which also holds items
+// the pharmacy has no stock of yet, and creates it if the hospital sent something new.
+func findOrCreateItem(tx *sql.Tx, name string, now time.Time) (int64, error) {
+	var id int64
+	err := tx.QueryRow("SELECT id FROM items WHERE name = ?", name).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	res, err := tx.Exec("INSERT INTO items (name, description, unit, price, created_at, updated_at) VALUES (?, ?, ?, 0, ?, ?)",
+		name, "Imported via Indent", "Units", now, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"billing-module/internal/core/domain"
+	"database/sql"
+	"fmt"
+)
+
+// --- KnowledgeRepository CRUD ---
+
+func (r *SQLiteRepository) ListAliases() ([]domain.Alias, error) {
+	rows, err := r.DB.Query("SELECT id, canonical_name, alias FROM medicine_aliases ORDER BY canonical_name, alias")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []domain.Alias{}
+	for rows.Next() {
+		var a domain.Alias
+		if err := rows.Scan(&a.ID, &a.CanonicalName, &a.Alias); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+func (r *SQLiteRepository) AddAlias(alias domain.Alias) (int64, error) {
+	if err := r.checkAliasFree(alias, 0); err != nil {
+		return 0, err
+	}
+	res, err := r.DB.Exec("INSERT INTO medicine_aliases (canonical_name, alias) VALUES (?, ?)", alias.CanonicalName, alias.Alias)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *SQLiteRepository) UpdateAlias(id string, alias domain.Alias) error {
+	var existingID int
+	if err := r.DB.QueryRow("SELECT id FROM medicine_aliases WHERE id = ?", id).Scan(&existingID); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: alias %s", domain.ErrNotFound, id)
+		}
+		return err
+	}
+	if err := r.checkAliasFree(alias, existingID); err != nil {
+		return err
+	}
+	_, err := r.DB.Exec("UPDATE medicine_aliases SET canonical_name=?, alias=? WHERE id=?", alias.CanonicalName, alias.Alias, existingID)
+	return err
+}
+
+func (r *SQLiteRepository) DeleteAlias(id string) error {
+	res, err := r.DB.Exec("DELETE FROM medicine_aliases WHERE id=?", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, fmt.Sprintf("alias %s", id))
+}
+
+// RenameCanonical moves every alias of oldName to newName. Aliases newName already has are dropped
+// rather than duplicated. It returns domain.ErrNotFound if oldName has no aliases.
+func (r *SQLiteRepository) RenameCanonical(oldName, newName string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Counted up front: when every alias clashes with one newName has, the update touches no rows
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM medicine_aliases WHERE canonical_name=?", oldName).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: canonical name %q", domain.ErrNotFound, oldName)
+	}
+	// Renaming to the same name would drop every alias as a duplicate of itself
+	if newName == oldName {
+		return nil
+	}
+
+	if _, err := tx.Exec("UPDATE OR IGNORE medicine_aliases SET canonical_name=? WHERE canonical_name=?", newName, oldName); err != nil {
+		return err
+	}
+	// Rows left behind by OR IGNORE are duplicates of aliases newName already has
+	if _, err := tx.Exec("DELETE FROM medicine_aliases WHERE canonical_name=? AND alias IN (SELECT alias FROM medicine_aliases WHERE canonical_name=?)",
+		oldName, newName); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SQLiteRepository) DeleteCanonical(name string) error {
+	res, err := r.DB.Exec("DELETE FROM medicine_aliases WHERE canonical_name=?", name)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res, fmt.Sprintf("canonical name %q", name))
+}
+
+// checkAliasFree returns ErrDuplicate if the canonical/alias pair exists on a row other than exceptID.
+func (r *SQLiteRepository) checkAliasFree(alias domain.Alias, exceptID int) error {
+	var count int
+	err := r.DB.QueryRow("SELECT COUNT(*) FROM medicine_aliases WHERE canonical_name=? AND alias=? AND id != ?",
+		alias.CanonicalName, alias.Alias, exceptID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %q is already an alias of %s", domain.ErrDuplicate, alias.Alias, alias.CanonicalName)
+	}
+	return nil
+}
+
+func requireAffected(res sql.Result, what string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, what)
+	}
+	return nil
+}
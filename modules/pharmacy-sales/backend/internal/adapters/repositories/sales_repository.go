@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"billing-module/internal/core/domain"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// --- SalesRepository Implementation ---
+
+// CommitSale deducts stock for every sale line, earliest expiry first, and records the
+// invoice inside a single transaction. If any line cannot be fully covered the whole sale is rolled back.
+func (r *SQLiteRepository) CommitSale(customerName string, lines []domain.SaleItem) (*domain.Invoice, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	invoice := &domain.Invoice{
+		CreatedAt:    now,
+		CustomerName: customerName,
+	}
+
+	for _, line := range lines {
+		allocation, err := allocateFIFO(tx, line, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range allocation.Batches {
+			invoice.Lines = append(invoice.Lines, priceLine(allocation, b, line.Discount, line.TaxRate))
+		}
+	}
+
+	for _, l := range invoice.Lines {
+		invoice.Subtotal += l.MRP * float64(l.Quantity)
+		invoice.DiscountTotal += l.Discount
+		invoice.TaxTotal += l.TaxAmount
+		invoice.GrandTotal += l.LineTotal
+	}
+	invoice.Subtotal = roundMoney(invoice.Subtotal)
+	invoice.DiscountTotal = roundMoney(invoice.DiscountTotal)
+	invoice.TaxTotal = roundMoney(invoice.TaxTotal)
+	invoice.GrandTotal = roundMoney(invoice.GrandTotal)
+
+	// Invoice numbers follow the row id so they stay sequential for the cash drawer. The id is only
+	// known once the row is in, so it goes in under a throwaway unique number first.
+	res, err := tx.Exec("INSERT INTO sales_invoices (invoice_number, created_at, customer_name, subtotal, discount_total, tax_total, grand_total) VALUES ('PENDING-' || hex(randomblob(8)), ?, ?, ?, ?, ?, ?)",
+		now.Format(time.RFC3339), invoice.CustomerName, invoice.Subtotal, invoice.DiscountTotal, invoice.TaxTotal, invoice.GrandTotal)
+	if err != nil {
+		return nil, err
+	}
+	invoiceID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	invoice.ID = int(invoiceID)
+	invoice.InvoiceNumber = fmt.Sprintf("INV-%06d", invoice.ID)
+	if _, err := tx.Exec("UPDATE sales_invoices SET invoice_number=? WHERE id=?", invoice.InvoiceNumber, invoice.ID); err != nil {
+		return nil, err
+	}
+
+	for i := range invoice.Lines {
+		l := &invoice.Lines[i]
+		l.InvoiceID = invoice.ID
+		res, err := tx.Exec("INSERT INTO sales_invoice_lines (invoice_id, item_id, item_name, batch_id, batch_number, expiry_date, mrp, quantity, discount, tax_rate, tax_amount, line_total) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			l.InvoiceID, l.ItemID, l.ItemName, l.BatchID, l.BatchNumber, l.Expiry.Format(time.RFC3339), l.MRP, l.Quantity, l.Discount, l.TaxRate, l.TaxAmount, l.LineTotal)
+		if err != nil {
+			return nil, err
+		}
+		lineID, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		l.ID = int(lineID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// ListInvoices returns invoice headers, newest first. Zero times leave the range open.
+func (r *SQLiteRepository) ListInvoices(from, to time.Time) ([]domain.Invoice, error) {
+	query := "SELECT id, invoice_number, created_at, coalesce(customer_name,''), subtotal, discount_total, tax_total, grand_total FROM sales_invoices WHERE 1=1"
+	var args []interface{}
+	if !from.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, from.UTC().Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query += " AND created_at < ?"
+		args = append(args, to.UTC().Format(time.RFC3339))
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []domain.Invoice{}
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, inv)
+	}
+	return invoices, rows.Err()
+}
+
+// GetInvoice returns a single invoice with its lines, for reprinting.
+func (r *SQLiteRepository) GetInvoice(id string) (*domain.Invoice, error) {
+	row := r.DB.QueryRow("SELECT id, invoice_number, created_at, coalesce(customer_name,''), subtotal, discount_total, tax_total, grand_total FROM sales_invoices WHERE id = ?", id)
+	inv, err := scanInvoice(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: invoice %s", domain.ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query("SELECT id, invoice_id, item_id, item_name, batch_id, batch_number, coalesce(expiry_date,''), mrp, quantity, discount, tax_rate, tax_amount, line_total FROM sales_invoice_lines WHERE invoice_id = ? ORDER BY id", inv.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l domain.InvoiceLine
+		var expiryStr string
+		if err := rows.Scan(&l.ID, &l.InvoiceID, &l.ItemID, &l.ItemName, &l.BatchID, &l.BatchNumber, &expiryStr, &l.MRP, &l.Quantity, &l.Discount, &l.TaxRate, &l.TaxAmount, &l.LineTotal); err != nil {
+			return nil, err
+		}
+		if parsed, err := time.Parse(time.RFC3339, expiryStr); err == nil {
+			l.Expiry = parsed
+		}
+		inv.Lines = append(inv.Lines, l)
+	}
+	return &inv, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanInvoice(row rowScanner) (domain.Invoice, error) {
+	var inv domain.Invoice
+	var createdStr string
+	if err := row.Scan(&inv.ID, &inv.InvoiceNumber, &createdStr, &inv.CustomerName, &inv.Subtotal, &inv.DiscountTotal, &inv.TaxTotal, &inv.GrandTotal); err != nil {
+		return inv, err
+	}
+	if parsed, err := time.Parse(time.RFC3339, createdStr); err == nil {
+		inv.CreatedAt = parsed
+	}
+	return inv, nil
+}
+
+// priceLine prices one batch allocation. MRP is tax inclusive, so the tax is carved
+// out of the discounted amount rather than added on top.
+func priceLine(allocation domain.SaleAllocation, b domain.BatchAllocation, discountPercent, taxRate float64) domain.InvoiceLine {
+	gross := b.MRP * float64(b.Quantity)
+	discount := roundMoney(gross * discountPercent / 100)
+	total := roundMoney(gross - discount)
+	tax := roundMoney(total - total/(1+taxRate/100))
+
+	return domain.InvoiceLine{
+		ItemID:      allocation.ItemID,
+		ItemName:    allocation.ItemName,
+		BatchID:     b.BatchID,
+		BatchNumber: b.BatchNumber,
+		Expiry:      b.Expiry,
+		MRP:         b.MRP,
+		Quantity:    b.Quantity,
+		Discount:    discount,
+		TaxRate:     taxRate,
+		TaxAmount:   tax,
+		LineTotal:   total,
+	}
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// allocateFIFO draws a single sale line from the unexpired batches of its item.
+func allocateFIFO(tx *sql.Tx, line domain.SaleItem, now time.Time) (domain.SaleAllocation, error) {
+	allocation := domain.SaleAllocation{
+		ItemID:   line.MatchedItem.ID,
+		ItemName: line.MatchedItem.Name,
+		Quantity: line.Quantity,
+		Batches:  []domain.BatchAllocation{},
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, batch_number, expiry_date, quantity, coalesce(mrp,0)
+		FROM pharmacy_batches
+		WHERE item_id = ? AND deleted_at IS NULL AND quantity > 0
+		ORDER BY expiry_date ASC, id ASC
+	`, line.MatchedItem.ID)
+	if err != nil {
+		return allocation, err
+	}
+
+	// Read all candidate batches before updating, the transaction holds a single connection.
+	var batches []domain.Batch
+	for rows.Next() {
+		var b domain.Batch
+		var expiryStr string
+		if err := rows.Scan(&b.ID, &b.BatchNumber, &expiryStr, &b.Quantity, &b.MRP); err != nil {
+			rows.Close()
+			return allocation, err
+		}
+		if parsed, err := time.Parse(time.RFC3339, expiryStr); err == nil {
+			b.Expiry = parsed
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return allocation, err
+	}
+
+	remaining := line.Quantity
+	for _, b := range batches {
+		if remaining <= 0 {
+			break
+		}
+		// Never sell expired stock
+		if !b.Expiry.IsZero() && b.Expiry.Before(now) {
+			continue
+		}
+
+		take := b.Quantity
+		if take > remaining {
+			take = remaining
+		}
+
+		res, err := tx.Exec("UPDATE pharmacy_batches SET quantity = quantity - ?, updated_at = ? WHERE id = ? AND quantity >= ?",
+			take, now, b.ID, take)
+		if err != nil {
+			return allocation, err
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			return allocation, fmt.Errorf("%w: batch %s changed during sale", domain.ErrInsufficientStock, b.BatchNumber)
+		}
+
+		allocation.Batches = append(allocation.Batches, domain.BatchAllocation{
+			BatchID:     b.ID,
+			BatchNumber: b.BatchNumber,
+			Expiry:      b.Expiry,
+			Quantity:    take,
+			MRP:         b.MRP,
+		})
+		remaining -= take
+	}
+
+	if remaining > 0 {
+		return allocation, fmt.Errorf("%w: %s needs %d, only %d available",
+			domain.ErrInsufficientStock, line.MatchedItem.Name, line.Quantity, line.Quantity-remaining)
+	}
+
+	return allocation, nil
+}
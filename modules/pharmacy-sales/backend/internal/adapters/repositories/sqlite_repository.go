@@ -23,7 +23,7 @@ func (r *SQLiteRepository) GetAllItems() ([]domain.Item, error) {
 	// First fetch all items
 	// First fetch all items (ignore soft deleted) that have associated batches
 	rows, err := r.DB.Query(`
-		SELECT DISTINCT i.id, i.name, coalesce(i.description,''), coalesce(i.threshold,10), coalesce(i.unit,'Unit'), i.price 
+		SELECT DISTINCT i.id, i.name, coalesce(i.description,''), coalesce(i.threshold,10), coalesce(i.unit,'Unit'), coalesce(i.pack_size,0), i.price 
 		FROM items i
 		JOIN pharmacy_batches b ON i.id = b.item_id
 		WHERE i.deleted_at IS NULL AND b.deleted_at IS NULL
@@ -38,7 +38,7 @@ func (r *SQLiteRepository) GetAllItems() ([]domain.Item, error) {
 
 	for rows.Next() {
 		var i domain.Item
-		if err := rows.Scan(&i.ID, &i.Name, &i.Description, &i.Threshold, &i.Unit, &i.Price); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Description, &i.Threshold, &i.Unit, &i.PackSize, &i.Price); err != nil {
 			return nil, err
 		}
 		i.Batches = []domain.Batch{}
@@ -83,7 +83,7 @@ func (r *SQLiteRepository) GetAllItems() ([]domain.Item, error) {
 
 func (r *SQLiteRepository) GetKnowledgeBase() ([]domain.Item, error) {
 	// Fetch all items from the master items table
-	rows, err := r.DB.Query(`SELECT id, name, coalesce(description,''), coalesce(threshold,10), coalesce(unit,'Unit'), price FROM items WHERE deleted_at IS NULL`)
+	rows, err := r.DB.Query(`SELECT id, name, coalesce(description,''), coalesce(threshold,10), coalesce(unit,'Unit'), coalesce(pack_size,0), price FROM items WHERE deleted_at IS NULL`)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +92,7 @@ func (r *SQLiteRepository) GetKnowledgeBase() ([]domain.Item, error) {
 	var items []domain.Item
 	for rows.Next() {
 		var i domain.Item
-		if err := rows.Scan(&i.ID, &i.Name, &i.Description, &i.Threshold, &i.Unit, &i.Price); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Description, &i.Threshold, &i.Unit, &i.PackSize, &i.Price); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -101,8 +101,8 @@ func (r *SQLiteRepository) GetKnowledgeBase() ([]domain.Item, error) {
 }
 
 func (r *SQLiteRepository) CreateItem(item domain.Item) (int64, error) {
-	res, err := r.DB.Exec("INSERT INTO items (name, description, threshold, unit, price, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
-		item.Name, item.Description, item.Threshold, item.Unit, item.Price, time.Now(), time.Now())
+	res, err := r.DB.Exec("INSERT INTO items (name, description, threshold, unit, pack_size, price, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		item.Name, item.Description, item.Threshold, item.Unit, item.PackSize, item.Price, time.Now(), time.Now())
 	if err != nil {
 		return 0, err
 	}
@@ -110,8 +110,9 @@ func (r *SQLiteRepository) CreateItem(item domain.Item) (int64, error) {
 }
 
 func (r *SQLiteRepository) UpdateItem(id string, item domain.Item) error {
-	_, err := r.DB.Exec("UPDATE items SET name=?, description=?, threshold=? WHERE id=?",
-		item.Name, item.Description, item.Threshold, id)
+	// A zero pack size keeps the stored one, older clients do not send it
+	_, err := r.DB.Exec("UPDATE items SET name=?, description=?, threshold=?, pack_size=coalesce(nullif(?,0), pack_size) WHERE id=?",
+		item.Name, item.Description, item.Threshold, item.PackSize, id)
 	return err
 }
 
@@ -0,0 +1,20 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrInvalidSale is returned when a sale line is not linked to an inventory item
+	// or carries a non-positive quantity.
+	ErrInvalidSale = errors.New("invalid sale")
+	// ErrInsufficientStock is returned when the unexpired batches of an item cannot
+	// cover the requested quantity.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrNotFound is returned when a requested record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicate is returned when a record would collide with an existing one.
+	ErrDuplicate = errors.New("already exists")
+	// ErrInvalidAlias is returned when an alias or canonical name is empty.
+	ErrInvalidAlias = errors.New("invalid alias")
+	// ErrUnknownItem is returned when a canonical name does not match any item.
+	ErrUnknownItem = errors.New("unknown item")
+)
@@ -8,7 +8,8 @@ type Item struct {
 	Name          string  `json:"name"`
 	Description   string  `json:"description"`
 	Threshold     int     `json:"threshold"`
-	Unit          string  `json:"unit"`
+	Unit          string  `json:"unit"`      // Base unit stock and sales are counted in, e.g. "Tablet"
+	PackSize      int     `json:"pack_size"` // Base units per strip/pack, e.g. 15 tablets per strip
 	Price         float64 `json:"price"`
 	TotalQuantity int     `json:"total_quantity"` // Aggregated from batches
 	Batches       []Batch `json:"batches"`
@@ -26,11 +27,96 @@ type Batch struct {
 	Location    string    `json:"location"`
 }
 
+// IndentReceiptLine is one dispatched line of a hospital indent, as booked into pharmacy stock
+type IndentReceiptLine struct {
+	ItemName string
+	Batches  []Batch
+}
+
+// Alias maps a name used at the counter to the canonical item name
+type Alias struct {
+	ID            int    `json:"id"`
+	CanonicalName string `json:"canonical_name"`
+	Alias         string `json:"alias"`
+}
+
+// MatchFeedback counts how often the pharmacist picked an item for a captured name
+type MatchFeedback struct {
+	ID            int    `json:"id"`
+	CapturedText  string `json:"captured_text"` // Normalised name part of the note, e.g. "gas wali goli"
+	CanonicalName string `json:"canonical_name"`
+	Confirmations int    `json:"confirmations"`
+	Promoted      bool   `json:"promoted"` // Copied into medicine_aliases
+}
+
 // SaleItem represents an item identified from a sales note
 type SaleItem struct {
-	CapturedName string  `json:"captured_name"`
-	MatchedItem  Item    `json:"matched_item"`
-	Quantity     int     `json:"quantity"`
+	CapturedName  string           `json:"captured_name"`
+	CapturedStart int              `json:"captured_start"` // Rune offsets of CapturedName within the note
+	CapturedEnd   int              `json:"captured_end"`
+	MatchedItem   Item             `json:"matched_item"`
+	Quantity      int              `json:"quantity"`                // In MatchedItem's base unit
+	Unit          string           `json:"unit,omitempty"`          // Unit as written in the note
+	UnitQuantity  float64          `json:"unit_quantity,omitempty"` // Quantity as written in the note, e.g. 0.5 for "half strip"
+	Confidence    float64          `json:"confidence"`
+	Status        string           `json:"status"`                     // "Available", "OutOfStock", "Unknown"
+	MatchedAlias  string           `json:"matched_alias,omitempty"`    // Alias or canonical name that matched
+	Alternatives  []MatchCandidate `json:"alternatives,omitempty"`     // Runner-up matches for one-tap correction
+	Discount      float64          `json:"discount_percent,omitempty"` // Set at checkout
+	TaxRate       float64          `json:"tax_rate,omitempty"`         // GST % included in MRP, set at checkout
+}
+
+// BatchAllocation records how much of a sale line was drawn from a single batch
+type BatchAllocation struct {
+	BatchID     int       `json:"batch_id"`
+	BatchNumber string    `json:"batch_number"`
+	Expiry      time.Time `json:"expiry_date"`
+	Quantity    int       `json:"quantity"`
+	MRP         float64   `json:"mrp"`
+}
+
+// SaleAllocation is the batch-level breakdown of one committed sale line
+type SaleAllocation struct {
+	ItemID   int               `json:"item_id"`
+	ItemName string            `json:"item_name"`
+	Quantity int               `json:"quantity"`
+	Batches  []BatchAllocation `json:"batches"`
+}
+
+// MatchCandidate is a ranked alternative for a captured name
+type MatchCandidate struct {
+	Item         Item    `json:"item"`
 	Confidence   float64 `json:"confidence"`
-	Status       string  `json:"status"` // "Available", "OutOfStock", "Unknown"
+	Status       string  `json:"status"` // "Available", "OutOfStock"
+	MatchedAlias string  `json:"matched_alias"`
+}
+
+// Invoice is the persisted record of a committed sale
+type Invoice struct {
+	ID            int           `json:"id"`
+	InvoiceNumber string        `json:"invoice_number"`
+	CreatedAt     time.Time     `json:"created_at"`
+	CustomerName  string        `json:"customer_name"`
+	Subtotal      float64       `json:"subtotal"` // MRP x quantity before discount
+	DiscountTotal float64       `json:"discount_total"`
+	TaxTotal      float64       `json:"tax_total"` // Tax component included in the total
+	GrandTotal    float64       `json:"grand_total"`
+	Lines         []InvoiceLine `json:"lines,omitempty"`
+}
+
+// InvoiceLine is one batch drawn for a sale line; a sale line spanning two batches becomes two invoice lines
+type InvoiceLine struct {
+	ID          int       `json:"id"`
+	InvoiceID   int       `json:"invoice_id"`
+	ItemID      int       `json:"item_id"`
+	ItemName    string    `json:"item_name"`
+	BatchID     int       `json:"batch_id"`
+	BatchNumber string    `json:"batch_number"`
+	Expiry      time.Time `json:"expiry_date"`
+	MRP         float64   `json:"mrp"`
+	Quantity    int       `json:"quantity"`
+	Discount    float64   `json:"discount"` // Amount, not percent
+	TaxRate     float64   `json:"tax_rate"`
+	TaxAmount   float64   `json:"tax_amount"`
+	LineTotal   float64   `json:"line_total"`
 }
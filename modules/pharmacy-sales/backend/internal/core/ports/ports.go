@@ -1,6 +1,9 @@
 package ports
 
-import "billing-module/internal/core/domain"
+import (
+	"billing-module/internal/core/domain"
+	"time"
+)
 
 type ItemRepository interface {
 	GetAllItems() ([]domain.Item, error)
@@ -11,16 +14,48 @@ type ItemRepository interface {
 	UpdateBatch(id string, batch domain.Batch) error
 	DeleteBatch(id string) error
 	DeleteItem(id string) error
+	// ReceiveIndent books every line's batches in one transaction, creating items the pharmacy
+	// has never stocked. It returns domain.ErrDuplicate if the indent was booked before.
+	ReceiveIndent(indentID int, lines []domain.IndentReceiptLine) error
 	SeedData() // For demo purposes
 }
 
 type KnowledgeRepository interface {
 	GetAllAliases() (map[string][]string, error)
+	ListAliases() ([]domain.Alias, error)
+	AddAlias(alias domain.Alias) (int64, error)
+	UpdateAlias(id string, alias domain.Alias) error
+	DeleteAlias(id string) error
+	RenameCanonical(oldName, newName string) error
+	DeleteCanonical(name string) error
 	SeedKnowledge() // For demo purposes
 }
 
+type FeedbackRepository interface {
+	RecordFeedback(capturedText, canonicalName string) (*domain.MatchFeedback, error)
+	MarkFeedbackPromoted(id int) error
+	GetFeedbackCounts() (map[string]map[string]int, error)
+}
+
+type SalesRepository interface {
+	CommitSale(customerName string, lines []domain.SaleItem) (*domain.Invoice, error)
+	ListInvoices(from, to time.Time) ([]domain.Invoice, error)
+	GetInvoice(id string) (*domain.Invoice, error)
+}
+
+// CatalogCache is implemented by services that cache items, batches or aliases and
+// must be told when they change.
+type CatalogCache interface {
+	InvalidateCatalog()
+}
+
 type BillingService interface {
 	ProcessNote(note string) []domain.SaleItem
+	CommitSale(customerName string, lines []domain.SaleItem) (*domain.Invoice, error)
+	ListInvoices(from, to time.Time) ([]domain.Invoice, error)
+	GetInvoice(id string) (*domain.Invoice, error)
+	RecordCorrection(capturedName, itemName string) (*domain.MatchFeedback, error)
+	InvalidateCatalog()
 }
 
 type InventoryService interface {
@@ -34,3 +69,12 @@ type InventoryService interface {
 	DeleteItem(id string) error
 	ReceiveIndent(indentID int) error
 }
+
+type KnowledgeService interface {
+	ListAliases() ([]domain.Alias, error)
+	AddAlias(alias domain.Alias) (int64, error)
+	UpdateAlias(id string, alias domain.Alias) error
+	DeleteAlias(id string) error
+	RenameCanonical(oldName, newName string) error
+	DeleteCanonical(name string) error
+}
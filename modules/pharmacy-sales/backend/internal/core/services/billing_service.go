@@ -4,72 +4,252 @@ import (
 	"billing-module/internal/core/domain"
 	"billing-module/internal/core/ports"
 	"billing-module/internal/core/services/sales"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"time"
 )
 
+// catalogTTL bounds how stale the cached catalog can get when items change outside this
+// service, e.g. the hospital module writing to the shared items table.
+const catalogTTL = 5 * time.Minute
+
+// catalog is a snapshot of the data notes are matched against
+type catalog struct {
+	matcher *sales.Matcher
+	vocab   *sales.Vocabulary
+	builtAt time.Time
+}
+
 type BillingService struct {
 	itemRepo      ports.ItemRepository
 	knowledgeRepo ports.KnowledgeRepository
+	salesRepo     ports.SalesRepository
+	feedbackRepo  ports.FeedbackRepository
+
+	// promotionThreshold is how many identical corrections turn a captured name into an alias
+	promotionThreshold int
+
+	mu      sync.Mutex
+	catalog *catalog // nil until first use or after InvalidateCatalog
 }
 
-func NewBillingService(itemRepo ports.ItemRepository, knowledgeRepo ports.KnowledgeRepository) *BillingService {
+func NewBillingService(
+	itemRepo ports.ItemRepository,
+	knowledgeRepo ports.KnowledgeRepository,
+	salesRepo ports.SalesRepository,
+	feedbackRepo ports.FeedbackRepository,
+	promotionThreshold int,
+) *BillingService {
 	return &BillingService{
-		itemRepo:      itemRepo,
-		knowledgeRepo: knowledgeRepo,
+		itemRepo:           itemRepo,
+		knowledgeRepo:      knowledgeRepo,
+		salesRepo:          salesRepo,
+		feedbackRepo:       feedbackRepo,
+		promotionThreshold: promotionThreshold,
 	}
 }
 
+// InvalidateCatalog drops the cached matching index; the next note rebuilds it.
+func (s *BillingService) InvalidateCatalog() {
+	s.mu.Lock()
+	s.catalog = nil
+	s.mu.Unlock()
+}
+
+// loadCatalog returns the cached matching index, rebuilding it when missing or stale.
+func (s *BillingService) loadCatalog() (*catalog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.catalog != nil && time.Since(s.catalog.builtAt) < catalogTTL {
+		return s.catalog, nil
+	}
+
+	items, err := s.itemRepo.GetAllItems()
+	if err != nil {
+		return nil, fmt.Errorf("fetching items: %w", err)
+	}
+
+	knowledgeBase, err := s.knowledgeRepo.GetAllAliases()
+	if err != nil {
+		return nil, fmt.Errorf("fetching knowledge base: %w", err)
+	}
+
+	feedback, err := s.feedbackRepo.GetFeedbackCounts()
+	if err != nil {
+		return nil, fmt.Errorf("fetching match feedback: %w", err)
+	}
+
+	s.catalog = &catalog{
+		matcher: sales.NewMatcher(items, knowledgeBase, feedback),
+		// Known strengths let the parser tell "dolo 650" from "dolo 2"
+		vocab:   sales.NewVocabulary(knowledgeBase, items),
+		builtAt: time.Now(),
+	}
+	return s.catalog, nil
+}
+
 func (s *BillingService) ProcessNote(note string) []domain.SaleItem {
 	var results []domain.SaleItem
 
-	// Parse
-	parsed := sales.ParseLine(note)
-	if parsed.LikelyName == "" {
+	// Split the note into one segment per item
+	segments := sales.SplitNote(note)
+	if len(segments) == 0 {
 		return results
 	}
 
-	// Fetch data for fuzzy search
-	// Optimization: In a real app, these should be cached.
-	items, err := s.itemRepo.GetAllItems()
+	c, err := s.loadCatalog()
 	if err != nil {
-		log.Printf("Error fetching items for billing: %v", err)
+		log.Printf("Error loading catalog for billing: %v", err)
 		return results
 	}
 
-	knowledgeBase, err := s.knowledgeRepo.GetAllAliases()
+	for _, segment := range segments {
+		// Parse
+		parsed := sales.ParseLine(segment.Text, c.vocab)
+		if parsed.LikelyName == "" {
+			continue
+		}
+
+		item := matchParsed(parsed, c.matcher)
+		item.CapturedName = segment.Text
+		item.CapturedStart = segment.Start
+		item.CapturedEnd = segment.End
+		results = append(results, item)
+	}
+
+	return results
+}
+
+// RecordCorrection remembers that the pharmacist picked itemName for capturedName. Once the
+// same correction has been confirmed promotionThreshold times it is added as an alias.
+func (s *BillingService) RecordCorrection(capturedName, itemName string) (*domain.MatchFeedback, error) {
+	canonical, err := resolveCanonical(s.itemRepo, itemName)
 	if err != nil {
-		log.Printf("Error fetching knowledge base for billing: %v", err)
-		return results
+		return nil, err
+	}
+
+	c, err := s.loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the name part only, the way it is looked up when matching ("pan 2" -> "pan")
+	captured := strings.ToLower(sales.ParseLine(capturedName, c.vocab).LikelyName)
+	if captured == "" {
+		return nil, fmt.Errorf("%w: captured name is required", domain.ErrInvalidAlias)
+	}
+
+	feedback, err := s.feedbackRepo.RecordFeedback(captured, canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	if !feedback.Promoted && s.promotionThreshold > 0 && feedback.Confirmations >= s.promotionThreshold {
+		_, err := s.knowledgeRepo.AddAlias(domain.Alias{CanonicalName: canonical, Alias: captured})
+		if err != nil && !errors.Is(err, domain.ErrDuplicate) {
+			return nil, err
+		}
+		if err := s.feedbackRepo.MarkFeedbackPromoted(feedback.ID); err != nil {
+			return nil, err
+		}
+		feedback.Promoted = true
 	}
 
+	s.InvalidateCatalog()
+	return feedback, nil
+}
+
+// maxMatchCandidates is how many ranked matches are returned per segment, the best plus alternatives
+const maxMatchCandidates = 5
+
+// matchParsed resolves a single parsed segment against the inventory and knowledge base.
+func matchParsed(parsed sales.ParsedItem, matcher *sales.Matcher) domain.SaleItem {
 	// Find
-	match := sales.FindBestMatch(parsed.LikelyName, items, knowledgeBase)
+	matches := matcher.Match(parsed.LikelyName, maxMatchCandidates)
 
-	if match != nil {
-		status := "Available"
-		if match.IsOutOfStock {
-			status = "OutOfStock"
+	if len(matches) > 0 {
+		best := matches[0]
+
+		var alternatives []domain.MatchCandidate
+		for _, m := range matches[1:] {
+			alternatives = append(alternatives, domain.MatchCandidate{
+				Item:         m.MatchedItem,
+				Confidence:   m.Confidence,
+				Status:       stockStatus(m),
+				MatchedAlias: m.MatchedAlias,
+			})
 		}
 
-		item := domain.SaleItem{
-			CapturedName: parsed.OriginalString,
-			MatchedItem:  match.MatchedItem,
-			Quantity:     parsed.Quantity,
-			Confidence:   match.Confidence,
-			Status:       status,
+		return domain.SaleItem{
+			MatchedItem:  best.MatchedItem,
+			Quantity:     sales.NormalizeQuantity(parsed.Quantity, parsed.Unit, best.MatchedItem),
+			Unit:         parsed.Unit,
+			UnitQuantity: parsed.Quantity,
+			Confidence:   best.Confidence,
+			Status:       stockStatus(best),
+			MatchedAlias: best.MatchedAlias,
+			Alternatives: alternatives,
 		}
-		results = append(results, item)
-	} else {
-		// Unknown Item
-		item := domain.SaleItem{
-			CapturedName: parsed.OriginalString,
-			MatchedItem:  domain.Item{Name: parsed.LikelyName, Price: 0, TotalQuantity: 0},
-			Quantity:     parsed.Quantity,
-			Confidence:   0,
-			Status:       "Unknown",
+	}
+
+	// Unknown Item
+	return domain.SaleItem{
+		MatchedItem:  domain.Item{Name: parsed.LikelyName, Price: 0, TotalQuantity: 0},
+		Quantity:     sales.NormalizeQuantity(parsed.Quantity, "", domain.Item{}),
+		Unit:         parsed.Unit,
+		UnitQuantity: parsed.Quantity,
+		Confidence:   0,
+		Status:       "Unknown",
+	}
+}
+
+func stockStatus(match sales.MatchResult) string {
+	if match.IsOutOfStock {
+		return "OutOfStock"
+	}
+	return "Available"
+}
+
+// CommitSale validates the confirmed sale lines, deducts their stock and records the invoice.
+// Either every line is allocated or nothing is written.
+func (s *BillingService) CommitSale(customerName string, lines []domain.SaleItem) (*domain.Invoice, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: no items to sell", domain.ErrInvalidSale)
+	}
+
+	for _, line := range lines {
+		if line.MatchedItem.ID == 0 {
+			return nil, fmt.Errorf("%w: %q is not linked to an inventory item", domain.ErrInvalidSale, line.CapturedName)
+		}
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity for %s must be positive", domain.ErrInvalidSale, line.MatchedItem.Name)
+		}
+		if line.Discount < 0 || line.Discount > 100 {
+			return nil, fmt.Errorf("%w: discount for %s must be between 0 and 100", domain.ErrInvalidSale, line.MatchedItem.Name)
+		}
+		if line.TaxRate < 0 || line.TaxRate > 100 {
+			return nil, fmt.Errorf("%w: tax rate for %s must be between 0 and 100", domain.ErrInvalidSale, line.MatchedItem.Name)
 		}
-		results = append(results, item)
 	}
 
-	return results
+	invoice, err := s.salesRepo.CommitSale(strings.TrimSpace(customerName), lines)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock levels changed, so cached stock statuses are stale
+	s.InvalidateCatalog()
+	return invoice, nil
+}
+
+func (s *BillingService) ListInvoices(from, to time.Time) ([]domain.Invoice, error) {
+	return s.salesRepo.ListInvoices(from, to)
+}
+
+func (s *BillingService) GetInvoice(id string) (*domain.Invoice, error) {
+	return s.salesRepo.GetInvoice(id)
 }
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"billing-module/internal/adapters/repositories"
+	"billing-module/internal/core/domain"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCommitSaleInvoiceNumbers(t *testing.T) {
+	db := repositories.InitDB(filepath.Join(t.TempDir(), "pharmacy.db"))
+	defer db.Close()
+	repo := repositories.NewSQLiteRepository(db)
+	itemID, err := repo.CreateItem(domain.Item{Name: "Dolo 650", Unit: "Tablet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.AddBatch(domain.Batch{ItemID: int(itemID), BatchNumber: "D1", Quantity: 3, MRP: 30, Expiry: time.Now().AddDate(1, 0, 0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewBillingService(repo, repo, repo, repo, 3)
+	sale := []domain.SaleItem{{MatchedItem: domain.Item{ID: int(itemID), Name: "Dolo 650"}, Quantity: 1}}
+	want := []string{"INV-000001", "INV-000002", "INV-000003"}
+	for i, number := range want {
+		invoice, err := service.CommitSale("", sale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if invoice.InvoiceNumber != number || invoice.InvoiceNumber != fmt.Sprintf("INV-%06d", invoice.ID) {
+			t.Errorf("sale %d: invoice %d numbered %q, want %q", i+1, invoice.ID, invoice.InvoiceNumber, number)
+		}
+		// A sale that fails leaves no invoice behind
+		if i == 0 {
+			big := []domain.SaleItem{{MatchedItem: sale[0].MatchedItem, Quantity: 10}}
+			if _, err := service.CommitSale("", big); !errors.Is(err, domain.ErrInsufficientStock) {
+				t.Fatalf("error = %v, want ErrInsufficientStock", err)
+			}
+		}
+	}
+
+	invoices, err := service.ListInvoices(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invoices) != len(want) {
+		t.Fatalf("stored %d invoices, want %d", len(invoices), len(want))
+	}
+	for i, invoice := range invoices {
+		// Newest first
+		if number := want[len(want)-1-i]; invoice.InvoiceNumber != number {
+			t.Errorf("stored invoice %d numbered %q, want %q", invoice.ID, invoice.InvoiceNumber, number)
+		}
+	}
+}
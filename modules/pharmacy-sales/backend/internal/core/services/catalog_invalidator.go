@@ -0,0 +1,16 @@
+package services
+
+import "billing-module/internal/core/ports"
+
+// catalogInvalidator is embedded by services whose writes change what sales notes can match.
+type catalogInvalidator struct {
+	cache ports.CatalogCache
+}
+
+// invalidateOnSuccess drops the billing catalog after a successful write and passes err through.
+func (c catalogInvalidator) invalidateOnSuccess(err error) error {
+	if err == nil {
+		c.cache.InvalidateCatalog()
+	}
+	return err
+}
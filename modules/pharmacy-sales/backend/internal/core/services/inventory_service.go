@@ -5,18 +5,20 @@ import (
 	"billing-module/internal/core/ports"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 )
 
 type InventoryService struct {
-	repo ports.ItemRepository
+	catalogInvalidator
+	repo        ports.ItemRepository
+	hospitalURL string // Base URL of the hospital backend indents are received from
 }
 
-func NewInventoryService(repo ports.ItemRepository) *InventoryService {
-	return &InventoryService{repo: repo}
+func NewInventoryService(repo ports.ItemRepository, cache ports.CatalogCache) *InventoryService {
+	return &InventoryService{repo: repo, hospitalURL: "http://localhost:8080", catalogInvalidator: catalogInvalidator{cache: cache}}
 }
 
 func (s *InventoryService) GetAllItems() ([]domain.Item, error) {
@@ -28,128 +30,94 @@ func (s *InventoryService) GetKnowledgeBase() ([]domain.Item, error) {
 }
 
 func (s *InventoryService) CreateItem(item domain.Item) (int64, error) {
-	return s.repo.CreateItem(item)
+	id, err := s.repo.CreateItem(item)
+	return id, s.invalidateOnSuccess(err)
 }
 
 func (s *InventoryService) UpdateItem(id string, item domain.Item) error {
-	return s.repo.UpdateItem(id, item)
+	return s.invalidateOnSuccess(s.repo.UpdateItem(id, item))
 }
 
 func (s *InventoryService) DeleteItem(id string) error {
-	return s.repo.DeleteItem(id)
+	return s.invalidateOnSuccess(s.repo.DeleteItem(id))
 }
 
 func (s *InventoryService) AddBatch(batch domain.Batch) (int64, error) {
-	return s.repo.AddBatch(batch)
+	id, err := s.repo.AddBatch(batch)
+	return id, s.invalidateOnSuccess(err)
 }
 
 func (s *InventoryService) UpdateBatch(id string, batch domain.Batch) error {
-	return s.repo.UpdateBatch(id, batch)
+	return s.invalidateOnSuccess(s.repo.UpdateBatch(id, batch))
 }
 
 func (s *InventoryService) DeleteBatch(id string) error {
-	return s.repo.DeleteBatch(id)
+	return s.invalidateOnSuccess(s.repo.DeleteBatch(id))
 }
 
-// ReceiveIndent fetches indent details from Hospital and ingests stock
+// ReceiveIndent fetches indent details from Hospital and ingests stock. The whole indent is
+// booked at once, and an indent booked before is not booked again, so a failed call can be retried.
 func (s *InventoryService) ReceiveIndent(indentID int) error {
-	fmt.Printf("Starting ReceiveIndent for ID: %d\n", indentID)
-
 	// 1. Fetch Indent from Hospital Backend
-	url := fmt.Sprintf("http://localhost:8080/api/indents/%d", indentID)
+	url := fmt.Sprintf("%s/api/indents/%d", s.hospitalURL, indentID)
 	resp, err := http.Get(url)
 	if err != nil {
-		fmt.Printf("Error fetching indent: %v\n", err)
 		return fmt.Errorf("failed to fetch indent: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error response from hospital: %d\n", resp.StatusCode)
 		return fmt.Errorf("failed to fetch indent: status %d", resp.StatusCode)
 	}
 
-	var indent struct {
-		ID              uint   `json:"id"`
+	// Indents from before multi-item indents carry a single item on the indent itself
+	type indentLine struct {
 		ItemName        string `json:"item_name"`
+		Status          string `json:"status"`
 		DispatchDetails string `json:"dispatch_details"`
 	}
-
-	// Read body first for debugging
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	fmt.Printf("Hospital Response: %s\n", string(bodyBytes))
-
-	// Decode
-	if err := json.Unmarshal(bodyBytes, &indent); err != nil {
-		fmt.Printf("Error decoding indent JSON: %v\n", err)
-		return fmt.Errorf("failed to decode indent: %v", err)
-	}
-
-	fmt.Printf("Parsed Indent: Name=%s, Details=%s\n", indent.ItemName, indent.DispatchDetails)
-
-	// 2. Parse Dispatch Details
-	type BatchDetail struct {
-		BatchNumber string    `json:"batch_number"`
-		Quantity    int       `json:"quantity"`
-		ExpiryDate  time.Time `json:"expiry_date"`
-		MRP         float64   `json:"mrp"`
-		Location    string    `json:"location"`
-	}
-	var details []BatchDetail
-	if err := json.Unmarshal([]byte(indent.DispatchDetails), &details); err != nil {
-		fmt.Printf("Error parsing DispatchDetails: %v\n", err)
-		return fmt.Errorf("failed to parse dispatch details: %v", err)
+	var indent struct {
+		ID              uint         `json:"id"`
+		ItemName        string       `json:"item_name"`
+		DispatchDetails string       `json:"dispatch_details"`
+		Lines           []indentLine `json:"lines"`
 	}
-
-	// 3. Find or Create Item
-	items, err := s.GetAllItems()
-	if err != nil {
-		return err
+	if err := json.NewDecoder(resp.Body).Decode(&indent); err != nil {
+		return fmt.Errorf("failed to decode indent: %v", err)
 	}
 
-	var targetItemID int
-	found := false
-	for _, item := range items {
-		if item.Name == indent.ItemName {
-			targetItemID = item.ID
-			found = true
-			break
-		}
+	lines := indent.Lines
+	if len(lines) == 0 {
+		lines = []indentLine{{ItemName: indent.ItemName, DispatchDetails: indent.DispatchDetails}}
 	}
 
-	if !found {
-		// Create Item
-		newItem := domain.Item{
-			Name: indent.ItemName,
-			// Category: "General", // Field does not exist in domain.Item
-			Description: "Imported via Indent",
-			Unit:        "Units",
+	// 2. Parse Dispatch Details of every line before booking any of them
+	var received []domain.IndentReceiptLine
+	for _, line := range lines {
+		// Only dispatched lines carry stock, rejected ones were never sent
+		if line.Status != "" && line.Status != "DISPATCHED" && line.Status != "PARTIALLY_DISPATCHED" {
+			continue
 		}
-		id, err := s.CreateItem(newItem)
+		batches, err := parseDispatchDetails(line.DispatchDetails)
 		if err != nil {
-			return err
+			return fmt.Errorf("%s: %w", line.ItemName, err)
 		}
-		targetItemID = int(id)
+		received = append(received, domain.IndentReceiptLine{ItemName: line.ItemName, Batches: batches})
 	}
 
-	// 4. Create Batches
-	for _, d := range details {
-		batch := domain.Batch{
-			ItemID:      targetItemID,
-			BatchNumber: d.BatchNumber,
-			Quantity:    d.Quantity,
-			Expiry:      d.ExpiryDate, // Field is Expiry
-			MRP:         d.MRP,        // Field is float64, not *float64
-			Location:    "Received-Indent",
-		}
-		if _, err := s.AddBatch(batch); err != nil {
-			return fmt.Errorf("failed to add batch %s: %v", d.BatchNumber, err)
-		}
+	// 3. Book the stock
+	err = s.repo.ReceiveIndent(indentID, received)
+	if errors.Is(err, domain.ErrDuplicate) {
+		// Booked by an earlier attempt whose confirmation failed, it only still needs confirming
+		err = nil
+	}
+	if err := s.invalidateOnSuccess(err); err != nil {
+		return err
 	}
 
-	// 5. Confirm Fulfillment
+	// 4. Confirm Fulfillment
 	client := &http.Client{}
-	req, err := http.NewRequest("PUT", fmt.Sprintf("http://localhost:8080/api/indents/%d/status", indentID), bytes.NewBuffer([]byte(`{"status": "FULFILLED"}`)))
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/api/indents/%d/status", s.hospitalURL, indentID), bytes.NewBuffer([]byte(`{"status": "FULFILLED"}`)))
 	if err != nil {
 		return err
 	}
@@ -167,3 +135,28 @@ func (s *InventoryService) ReceiveIndent(indentID int) error {
 
 	return nil
 }
+
+// parseDispatchDetails reads the batches the hospital listed as dispatched for one indent line.
+func parseDispatchDetails(dispatchDetails string) ([]domain.Batch, error) {
+	var details []struct {
+		BatchNumber string    `json:"batch_number"`
+		Quantity    int       `json:"quantity"`
+		ExpiryDate  time.Time `json:"expiry_date"`
+		MRP         float64   `json:"mrp"`
+	}
+	if err := json.Unmarshal([]byte(dispatchDetails), &details); err != nil {
+		return nil, fmt.Errorf("failed to parse dispatch details: %v", err)
+	}
+
+	batches := make([]domain.Batch, 0, len(details))
+	for _, d := range details {
+		batches = append(batches, domain.Batch{
+			BatchNumber: d.BatchNumber,
+			Quantity:    d.Quantity,
+			Expiry:      d.ExpiryDate,
+			MRP:         d.MRP,
+			Location:    "Received-Indent",
+		})
+	}
+	return batches, nil
+}
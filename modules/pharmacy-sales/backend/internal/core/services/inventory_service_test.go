@@ -0,0 +1,147 @@
+package services
+
+import (
+	"billing-module/internal/adapters/repositories"
+	"billing-module/internal/core/domain"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type countingCache struct{ invalidations int }
+
+func (c *countingCache) InvalidateCatalog() { c.invalidations++ }
+
+// fakeHospital serves one indent and records the status confirmations sent back.
+type fakeHospital struct {
+	indent      interface{}
+	confirmFail bool
+	confirmed   int
+}
+
+func (h *fakeHospital) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/indents/7":
+		json.NewEncoder(w).Encode(h.indent)
+	case r.Method == http.MethodPut && r.URL.Path == "/api/indents/7/status":
+		if h.confirmFail {
+			http.Error(w, "hospital is down", http.StatusServiceUnavailable)
+			return
+		}
+		h.confirmed++
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func dispatchDetails(t *testing.T, batches ...map[string]interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(batches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// stockByItem sums live batch quantities per item name, and counts the items of each name.
+func stockByItem(t *testing.T, db *sql.DB) (stock, items map[string]int) {
+	t.Helper()
+	stock, items = map[string]int{}, map[string]int{}
+	rows, err := db.Query(`SELECT i.name, count(DISTINCT i.id), coalesce(sum(b.quantity), 0)
+		FROM items i LEFT JOIN pharmacy_batches b ON b.item_id = i.id AND b.deleted_at IS NULL GROUP BY i.name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var count, qty int
+		if err := rows.Scan(&name, &count, &qty); err != nil {
+			t.Fatal(err)
+		}
+		items[name], stock[name] = count, qty
+	}
+	return stock, items
+}
+
+func TestReceiveIndent(t *testing.T) {
+	db := repositories.InitDB(filepath.Join(t.TempDir(), "pharmacy.db"))
+	defer db.Close()
+	repo := repositories.NewSQLiteRepository(db)
+	// Known to the shared items table, but never stocked by the pharmacy
+	if _, err := repo.CreateItem(domain.Item{Name: "Dolo 650", Unit: "Tablet"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expiry := time.Date(2028, 1, 31, 0, 0, 0, 0, time.UTC)
+	hospital := &fakeHospital{indent: map[string]interface{}{
+		"id": 7,
+		"lines": []map[string]interface{}{
+			{"item_name": "Zinc Syrup", "status": "DISPATCHED", "dispatch_details": dispatchDetails(t,
+				map[string]interface{}{"batch_number": "Z1", "quantity": 10, "expiry_date": expiry, "mrp": 85})},
+			// The same new item again, it must not be created twice
+			{"item_name": "Zinc Syrup", "status": "PARTIALLY_DISPATCHED", "dispatch_details": dispatchDetails(t,
+				map[string]interface{}{"batch_number": "Z2", "quantity": 5, "expiry_date": expiry, "mrp": 85})},
+			{"item_name": "Dolo 650", "status": "DISPATCHED", "dispatch_details": dispatchDetails(t,
+				map[string]interface{}{"batch_number": "D1", "quantity": 20, "expiry_date": expiry, "mrp": 30})},
+			{"item_name": "Gauze Roll", "status": "REJECTED", "dispatch_details": ""},
+		},
+	}}
+	server := httptest.NewServer(hospital)
+	defer server.Close()
+
+	cache := &countingCache{}
+	service := NewInventoryService(repo, cache)
+	service.hospitalURL = server.URL
+
+	// The last line fails to book, after the first two were written
+	if _, err := db.Exec(`CREATE TRIGGER fail_d1 BEFORE INSERT ON pharmacy_batches WHEN NEW.batch_number = 'D1'
+		BEGIN SELECT RAISE(ABORT, 'disk I/O error'); END`); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ReceiveIndent(7); err == nil {
+		t.Fatal("ReceiveIndent succeeded with a failing batch")
+	}
+	stock, items := stockByItem(t, db)
+	if stock["Zinc Syrup"] != 0 || items["Zinc Syrup"] != 0 || stock["Dolo 650"] != 0 {
+		t.Fatalf("after the failed attempt stock = %v, items = %v, want nothing booked", stock, items)
+	}
+	if hospital.confirmed != 0 || cache.invalidations != 0 {
+		t.Fatalf("failed attempt confirmed %d times and invalidated %d times, want neither", hospital.confirmed, cache.invalidations)
+	}
+
+	// The retry books everything, but the hospital cannot be told
+	if _, err := db.Exec("DROP TRIGGER fail_d1"); err != nil {
+		t.Fatal(err)
+	}
+	hospital.confirmFail = true
+	if err := service.ReceiveIndent(7); err == nil {
+		t.Fatal("ReceiveIndent succeeded without confirming")
+	}
+
+	// Retrying the confirmation does not book the stock again
+	hospital.confirmFail = false
+	if err := service.ReceiveIndent(7); err != nil {
+		t.Fatal(err)
+	}
+	stock, items = stockByItem(t, db)
+	want := map[string]int{"Zinc Syrup": 15, "Dolo 650": 20}
+	for name, qty := range want {
+		if stock[name] != qty || items[name] != 1 {
+			t.Errorf("%s: %d in stock over %d items, want %d on one item", name, stock[name], items[name], qty)
+		}
+	}
+	if items["Gauze Roll"] != 0 {
+		t.Error("rejected line was booked")
+	}
+	if hospital.confirmed != 1 {
+		t.Errorf("indent confirmed %d times, want 1", hospital.confirmed)
+	}
+	if cache.invalidations == 0 {
+		t.Error("catalog was not invalidated after booking stock")
+	}
+}
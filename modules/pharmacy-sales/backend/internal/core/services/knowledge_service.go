@@ -0,0 +1,102 @@
+package services
+
+import (
+	"billing-module/internal/core/domain"
+	"billing-module/internal/core/ports"
+	"fmt"
+	"strings"
+)
+
+// KnowledgeService manages the alias knowledge base used to match sales notes.
+type KnowledgeService struct {
+	catalogInvalidator
+	repo     ports.KnowledgeRepository
+	itemRepo ports.ItemRepository
+}
+
+func NewKnowledgeService(repo ports.KnowledgeRepository, itemRepo ports.ItemRepository, cache ports.CatalogCache) *KnowledgeService {
+	return &KnowledgeService{repo: repo, itemRepo: itemRepo, catalogInvalidator: catalogInvalidator{cache: cache}}
+}
+
+func (s *KnowledgeService) ListAliases() ([]domain.Alias, error) {
+	return s.repo.ListAliases()
+}
+
+func (s *KnowledgeService) AddAlias(alias domain.Alias) (int64, error) {
+	normalized, err := s.normalizeAlias(alias)
+	if err != nil {
+		return 0, err
+	}
+	id, err := s.repo.AddAlias(normalized)
+	return id, s.invalidateOnSuccess(err)
+}
+
+func (s *KnowledgeService) UpdateAlias(id string, alias domain.Alias) error {
+	normalized, err := s.normalizeAlias(alias)
+	if err != nil {
+		return err
+	}
+	return s.invalidateOnSuccess(s.repo.UpdateAlias(id, normalized))
+}
+
+func (s *KnowledgeService) DeleteAlias(id string) error {
+	return s.invalidateOnSuccess(s.repo.DeleteAlias(id))
+}
+
+// RenameCanonical points every alias of oldName at newName, which must be an existing item.
+func (s *KnowledgeService) RenameCanonical(oldName, newName string) error {
+	oldName = strings.TrimSpace(oldName)
+	if oldName == "" {
+		return fmt.Errorf("%w: canonical name is required", domain.ErrInvalidAlias)
+	}
+	canonical, err := resolveCanonical(s.itemRepo, newName)
+	if err != nil {
+		return err
+	}
+	// A case-only rename resolves back to the same stored spelling; the repository still
+	// reports an unknown oldName, but has nothing to move
+	err = s.repo.RenameCanonical(oldName, canonical)
+	if canonical == oldName {
+		return err
+	}
+	return s.invalidateOnSuccess(err)
+}
+
+func (s *KnowledgeService) DeleteCanonical(name string) error {
+	return s.invalidateOnSuccess(s.repo.DeleteCanonical(strings.TrimSpace(name)))
+}
+
+// normalizeAlias lower-cases the alias, as the matcher does, and resolves the canonical
+// name to the item's stored spelling.
+func (s *KnowledgeService) normalizeAlias(alias domain.Alias) (domain.Alias, error) {
+	alias.Alias = strings.ToLower(strings.TrimSpace(alias.Alias))
+	if alias.Alias == "" {
+		return alias, fmt.Errorf("%w: alias is required", domain.ErrInvalidAlias)
+	}
+
+	canonical, err := resolveCanonical(s.itemRepo, alias.CanonicalName)
+	if err != nil {
+		return alias, err
+	}
+	alias.CanonicalName = canonical
+	return alias, nil
+}
+
+// resolveCanonical finds the item a canonical name refers to, ignoring case.
+func resolveCanonical(itemRepo ports.ItemRepository, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: canonical name is required", domain.ErrInvalidAlias)
+	}
+
+	items, err := itemRepo.GetKnowledgeBase()
+	if err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		if strings.EqualFold(item.Name, name) {
+			return item.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no item named %q", domain.ErrUnknownItem, name)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"billing-module/internal/adapters/repositories"
+	"billing-module/internal/core/domain"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sortedAliases reads the knowledge base with each name's aliases in order.
+func sortedAliases(t *testing.T, repo *repositories.SQLiteRepository) map[string][]string {
+	t.Helper()
+	aliases, err := repo.GetAllAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, list := range aliases {
+		sort.Strings(list)
+	}
+	return aliases
+}
+
+func TestRenameCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldName  string
+		newName  string
+		wantErr  error
+		want     map[string][]string
+		wantSync bool // catalog invalidated
+	}{
+		{
+			name:    "moves aliases and drops duplicates",
+			oldName: "Dolo-650", newName: "paracetamol 500MG",
+			want:     map[string][]string{"Paracetamol 500mg": {"bukhar ki dawa", "dolo", "pcm"}},
+			wantSync: true,
+		},
+		{
+			name:    "case-only rename keeps everything",
+			oldName: "Dolo-650", newName: "dolo-650",
+			want: map[string][]string{"Dolo-650": {"bukhar ki dawa", "dolo", "pcm"}, "Paracetamol 500mg": {"pcm"}},
+		},
+		{name: "unknown old name", oldName: "Crocin", newName: "Dolo-650", wantErr: domain.ErrNotFound},
+		{name: "unknown old name that is an item", oldName: "Calpol", newName: "calpol", wantErr: domain.ErrNotFound},
+		{name: "new name is not an item", oldName: "Dolo-650", newName: "Dolo 650", wantErr: domain.ErrUnknownItem},
+		{name: "old name is required", oldName: " ", newName: "Dolo-650", wantErr: domain.ErrInvalidAlias},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := repositories.InitDB(filepath.Join(t.TempDir(), "pharmacy.db"))
+			defer db.Close()
+			repo := repositories.NewSQLiteRepository(db)
+			for _, name := range []string{"Dolo-650", "Paracetamol 500mg", "Calpol"} {
+				if _, err := repo.CreateItem(domain.Item{Name: name, Unit: "Tablet"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, a := range []domain.Alias{{CanonicalName: "Dolo-650", Alias: "dolo"}, {CanonicalName: "Dolo-650", Alias: "pcm"},
+				{CanonicalName: "Dolo-650", Alias: "bukhar ki dawa"}, {CanonicalName: "Paracetamol 500mg", Alias: "pcm"}} {
+				if _, err := repo.AddAlias(a); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before := sortedAliases(t, repo)
+
+			cache := &countingCache{}
+			err := NewKnowledgeService(repo, repo, cache).RenameCanonical(tt.oldName, tt.newName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.want
+			if tt.wantErr != nil {
+				want = before
+			}
+			if got := sortedAliases(t, repo); !reflect.DeepEqual(got, want) {
+				t.Errorf("aliases = %v, want %v", got, want)
+			}
+			if (cache.invalidations > 0) != tt.wantSync {
+				t.Errorf("catalog invalidated %d times, want it invalidated: %v", cache.invalidations, tt.wantSync)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ type MatchResult struct {
 	MatchedItem  domain.Item
 	Confidence   float64
 	IsOutOfStock bool
+	MatchedAlias string // The alias or canonical name that scored best
 }
 
 // simple levenshtein implementation
@@ -45,78 +46,72 @@ func levenshtein(s1, s2 string) int {
 }
 
 type conceptMatch struct {
-	ConceptName string
-	Score       float64
+	ConceptName  string
+	Score        float64
+	MatchedAlias string
 }
 
+// FindBestMatch returns the highest ranked match for the query, or nil if nothing is close enough.
 func FindBestMatch(query string, items []domain.Item, knowledgeBase map[string][]string) *MatchResult {
-	query = strings.ToLower(strings.TrimSpace(query))
-	bestMatch := &MatchResult{Confidence: 0.0}
+	matches := FindMatches(query, items, knowledgeBase, 1)
+	if len(matches) == 0 {
+		return nil
+	}
+	return &matches[0]
+}
 
-	// 1. Search Knowledge Base
-	var possibleConcepts []conceptMatch
+// FindMatches returns up to limit candidates for the query, best first, each linked to
+// inventory so the caller can show its stock status. It builds a throwaway index; callers
+// matching many queries against the same data should keep a Matcher instead.
+func FindMatches(query string, items []domain.Item, knowledgeBase map[string][]string, limit int) []MatchResult {
+	return NewMatcher(items, knowledgeBase, nil).Match(query, limit)
+}
 
-	for canonicalName, aliases := range knowledgeBase {
-		bestScore := 0.0
-		// Check canonical name
-		dist := levenshtein(query, strings.ToLower(canonicalName))
-		score := 1.0 - (float64(dist) / float64(max(len(query), len(canonicalName))))
+// Weights for blending spelling and sound similarity
+const (
+	editWeight     = 0.6
+	phoneticWeight = 0.4
+)
 
-		if strings.HasPrefix(strings.ToLower(canonicalName), query) {
-			score += 0.2 // Boost prefix
-		}
-		if score > bestScore {
-			bestScore = score
-		}
+// scoreTerm scores a lower-cased query against one alias or canonical name. The edit
+// distance score is blended with how close the two sound, and the blend only ever helps:
+// a near-exact spelling is not penalised for an unlucky phonetic key.
+func scoreTerm(query, queryKey, term, termKey string) float64 {
+	score := similarity(query, term)
 
-		// Check aliases
-		for _, alias := range aliases {
-			dist := levenshtein(query, alias)
-			aliasScore := 1.0 - (float64(dist) / float64(max(len(query), len(alias))))
-			if strings.HasPrefix(alias, query) {
-				aliasScore += 0.2
-			}
-			if aliasScore > bestScore {
-				bestScore = aliasScore
-			}
-		}
-
-		if bestScore > 0.4 {
-			possibleConcepts = append(possibleConcepts, conceptMatch{ConceptName: canonicalName, Score: bestScore})
+	if queryKey != "" && termKey != "" {
+		blended := editWeight*score + phoneticWeight*similarity(queryKey, termKey)
+		if blended > score {
+			score = blended
 		}
 	}
 
-	// Sort by score
-	sort.Slice(possibleConcepts, func(i, j int) bool {
-		return possibleConcepts[i].Score > possibleConcepts[j].Score
-	})
-
-	if len(possibleConcepts) == 0 {
-		return nil
+	if strings.HasPrefix(term, query) {
+		score += 0.2 // Boost prefix
 	}
+	return score
+}
 
-	bestConcept := possibleConcepts[0]
+// similarity is 1 for identical strings, falling towards 0 with edit distance.
+func similarity(a, b string) float64 {
+	dist := levenshtein(a, b)
+	return 1.0 - (float64(dist) / float64(max(len(a), len(b))))
+}
 
-	// 2. Link to Inventory
-	foundInInventory := false
-	for _, item := range items {
-		if strings.EqualFold(item.Name, bestConcept.ConceptName) {
-			bestMatch.MatchedItem = item
-			bestMatch.Confidence = bestConcept.Score
-			bestMatch.IsOutOfStock = false
-			foundInInventory = true
-			break
+// rankConcepts sorts by score, then name so equal scores rank the same way every time,
+// and keeps at most limit entries.
+func rankConcepts(concepts []conceptMatch, limit int) []conceptMatch {
+	sort.Slice(concepts, func(i, j int) bool {
+		if concepts[i].Score != concepts[j].Score {
+			return concepts[i].Score > concepts[j].Score
 		}
-	}
+		return concepts[i].ConceptName < concepts[j].ConceptName
+	})
 
-	// 3. Handle OOS
-	if !foundInInventory {
-		bestMatch.MatchedItem = domain.Item{Name: bestConcept.ConceptName, Price: 0, TotalQuantity: 0}
-		bestMatch.Confidence = bestConcept.Score
-		bestMatch.IsOutOfStock = true
+	if limit > 0 && len(concepts) > limit {
+		concepts = concepts[:limit]
 	}
-
-	return bestMatch
+	return concepts
 }
 
 func max(a, b int) int {
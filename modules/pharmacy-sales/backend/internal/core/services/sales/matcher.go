@@ -0,0 +1,184 @@
+package sales
+
+import (
+	"billing-module/internal/core/domain"
+	"math"
+	"strings"
+)
+
+// minMatchScore is the score a concept needs to be offered as a match at all
+const minMatchScore = 0.4
+
+// Pharmacist corrections boost a concept for the exact query they were made on,
+// by feedbackBoost per confirmation up to maxFeedbackBoost.
+const (
+	feedbackBoost    = 0.1
+	maxFeedbackBoost = 0.3
+)
+
+// phoneticGramPrefix keeps trigrams of phonetic keys apart from trigrams of spellings
+const phoneticGramPrefix = "~"
+
+// Matcher is a prebuilt index over the knowledge base and inventory. Aliases and canonical
+// names are indexed by trigram, of both their spelling and their phonetic key, so a query is
+// only scored against terms it shares text or sound with instead of every alias.
+// A Matcher is read-only once built and safe for concurrent use.
+type Matcher struct {
+	terms    []indexedTerm
+	trigrams map[string][]int          // trigram -> indexes into terms
+	items    map[string]domain.Item    // lower-cased item name -> stocked item
+	feedback map[string]map[string]int // lower-cased query -> canonical name -> confirmations
+}
+
+// indexedTerm is one searchable alias or canonical name
+type indexedTerm struct {
+	Text        string // Lower-cased
+	Key         string // Phonetic key of Text
+	ConceptName string // Canonical name the term resolves to
+	Original    string // As stored, reported back as the matched alias
+}
+
+// NewMatcher indexes every canonical name and alias in the knowledge base. feedback holds
+// how often each item was chosen for a query and may be nil.
+func NewMatcher(items []domain.Item, knowledgeBase map[string][]string, feedback map[string]map[string]int) *Matcher {
+	m := &Matcher{
+		trigrams: make(map[string][]int),
+		items:    make(map[string]domain.Item, len(items)),
+		feedback: make(map[string]map[string]int, len(feedback)),
+	}
+
+	for query, counts := range feedback {
+		m.feedback[strings.ToLower(strings.TrimSpace(query))] = counts
+	}
+
+	for _, item := range items {
+		m.items[strings.ToLower(item.Name)] = item
+	}
+
+	for canonicalName, aliases := range knowledgeBase {
+		m.addTerm(canonicalName, canonicalName)
+		for _, alias := range aliases {
+			m.addTerm(alias, canonicalName)
+		}
+	}
+	return m
+}
+
+func (m *Matcher) addTerm(term, conceptName string) {
+	text := strings.ToLower(strings.TrimSpace(term))
+	if text == "" {
+		return
+	}
+
+	id := len(m.terms)
+	key := phoneticKey(text)
+	m.terms = append(m.terms, indexedTerm{Text: text, Key: key, ConceptName: conceptName, Original: term})
+	for _, gram := range termGrams(text, key) {
+		m.trigrams[gram] = append(m.trigrams[gram], id)
+	}
+}
+
+// Match returns up to limit candidates for the query, best first.
+func (m *Matcher) Match(query string, limit int) []MatchResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	// 1. Collect terms sharing enough trigrams with the query
+	queryKey := phoneticKey(query)
+	queryGrams := termGrams(query, queryKey)
+	shared := make(map[int]int)
+	for _, gram := range queryGrams {
+		for _, id := range m.trigrams[gram] {
+			shared[id]++
+		}
+	}
+	minShared := (len(queryGrams) + 3) / 4
+
+	// 2. Score candidates, keeping the best term per concept
+	best := make(map[string]conceptMatch)
+	for id, count := range shared {
+		if count < minShared {
+			continue
+		}
+		term := m.terms[id]
+		score := scoreTerm(query, queryKey, term.Text, term.Key)
+		if current, ok := best[term.ConceptName]; !ok || score > current.Score {
+			best[term.ConceptName] = conceptMatch{ConceptName: term.ConceptName, Score: score, MatchedAlias: term.Original}
+		}
+	}
+
+	// 3. Items the pharmacist picked for this exact query before rank higher, even without a textual match
+	for conceptName, confirmations := range m.feedback[query] {
+		boost := math.Min(feedbackBoost*float64(confirmations), maxFeedbackBoost)
+		concept, ok := best[conceptName]
+		if !ok {
+			concept = conceptMatch{ConceptName: conceptName, Score: minMatchScore, MatchedAlias: query}
+		}
+		concept.Score += boost
+		best[conceptName] = concept
+	}
+
+	var possibleConcepts []conceptMatch
+	for _, concept := range best {
+		if concept.Score > minMatchScore {
+			possibleConcepts = append(possibleConcepts, concept)
+		}
+	}
+	possibleConcepts = rankConcepts(possibleConcepts, limit)
+
+	matches := make([]MatchResult, 0, len(possibleConcepts))
+	for _, concept := range possibleConcepts {
+		matches = append(matches, m.linkToInventory(concept))
+	}
+	return matches
+}
+
+// linkToInventory resolves a knowledge base concept to a stocked item.
+func (m *Matcher) linkToInventory(concept conceptMatch) MatchResult {
+	// Link to Inventory
+	if item, ok := m.items[strings.ToLower(concept.ConceptName)]; ok {
+		return MatchResult{
+			MatchedItem:  item,
+			Confidence:   concept.Score,
+			IsOutOfStock: item.TotalQuantity <= 0,
+			MatchedAlias: concept.MatchedAlias,
+		}
+	}
+
+	// Handle OOS
+	return MatchResult{
+		MatchedItem:  domain.Item{Name: concept.ConceptName, Price: 0, TotalQuantity: 0},
+		Confidence:   concept.Score,
+		IsOutOfStock: true,
+		MatchedAlias: concept.MatchedAlias,
+	}
+}
+
+// termGrams returns the spelling trigrams of text plus the prefixed trigrams of its phonetic key.
+func termGrams(text, key string) []string {
+	grams := trigrams(text)
+	if key != "" {
+		for _, gram := range trigrams(key) {
+			grams = append(grams, phoneticGramPrefix+gram)
+		}
+	}
+	return grams
+}
+
+// trigrams splits text into its distinct padded 3-rune grams, so short words still share
+// a leading gram with their prefixes ("pan" and "pantocid" share "  p", " pa" and "pan").
+func trigrams(text string) []string {
+	runes := []rune("  " + text + " ")
+	seen := make(map[string]bool, len(runes))
+	var grams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		gram := string(runes[i : i+3])
+		if !seen[gram] {
+			seen[gram] = true
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
@@ -0,0 +1,147 @@
+package sales
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// syllables make up the synthetic brand names, so they share trigrams the way real ones do.
+var syllables = []string{
+	"pa", "ra", "ce", "ta", "mol", "do", "lo", "cro", "cin", "pan", "to", "zol",
+	"az", "ith", "ro", "my", "mox", "ci", "lin", "ce", "tri", "zin", "met", "for",
+	"min", "mon", "te", "lu", "kast", "on", "dan", "se", "tron", "vi", "ta", "zy",
+}
+
+// syntheticCatalog builds a knowledge base with about aliases aliases, four per concept.
+// The same seed always gives the same catalog.
+func syntheticCatalog(aliases int, seed int64) map[string][]string {
+	rng := rand.New(rand.NewSource(seed))
+	word := func() string {
+		var b strings.Builder
+		for n := 2 + rng.Intn(3); n > 0; n-- {
+			b.WriteString(syllables[rng.Intn(len(syllables))])
+		}
+		return b.String()
+	}
+
+	kb := make(map[string][]string, aliases/4)
+	for i := 0; len(kb)*4 < aliases; i++ {
+		name := fmt.Sprintf("%s %d", word(), 10*(1+rng.Intn(50)))
+		if _, ok := kb[name]; ok {
+			continue
+		}
+		kb[name] = []string{word(), word(), word()}
+	}
+	return kb
+}
+
+// catalogTerms lists every canonical name and alias in kb in a fixed order.
+func catalogTerms(kb map[string][]string) []string {
+	var terms []string
+	for name, aliases := range kb {
+		terms = append(terms, strings.ToLower(name))
+		terms = append(terms, aliases...)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// misspell makes the kind of typing and sound-alike mistakes seen on counter notes.
+func misspell(rng *rand.Rand, s string) string {
+	runes := []rune(s)
+	i := rng.Intn(len(runes))
+	switch rng.Intn(4) {
+	case 0: // dropped letter
+		return string(append(runes[:i:i], runes[i+1:]...))
+	case 1: // doubled letter
+		return string(runes[:i+1]) + string(runes[i:])
+	case 2: // wrong vowel
+		if strings.ContainsRune("aeiou", runes[i]) {
+			runes[i] = rune("aeiou"[rng.Intn(5)])
+		}
+		return string(runes)
+	default: // spelt by sound
+		return strings.NewReplacer("ce", "se", "ci", "si", "ca", "ka", "co", "ko", "cu", "ku", "ph", "f", "th", "t").Replace(s)
+	}
+}
+
+// fullScan is the matcher before the trigram index: every term is scored against the query.
+func fullScan(m *Matcher, query string, limit int) []conceptMatch {
+	query = strings.ToLower(strings.TrimSpace(query))
+	queryKey := phoneticKey(query)
+
+	best := make(map[string]conceptMatch)
+	for _, term := range m.terms {
+		score := scoreTerm(query, queryKey, term.Text, term.Key)
+		if current, ok := best[term.ConceptName]; !ok || score > current.Score {
+			best[term.ConceptName] = conceptMatch{ConceptName: term.ConceptName, Score: score, MatchedAlias: term.Original}
+		}
+	}
+
+	var concepts []conceptMatch
+	for _, concept := range best {
+		if concept.Score > minMatchScore {
+			concepts = append(concepts, concept)
+		}
+	}
+	return rankConcepts(concepts, limit)
+}
+
+// The trigram index only skips terms too far from the query to win, so the top match
+// must be the one scoring every term would give.
+func TestMatcherAgreesWithFullScan(t *testing.T) {
+	kb := syntheticCatalog(5000, 1)
+	m := NewMatcher(nil, kb, nil)
+
+	rng := rand.New(rand.NewSource(2))
+	terms := catalogTerms(kb)
+	var queries []string
+	for i := 0; i < 150; i++ {
+		term := terms[rng.Intn(len(terms))]
+		queries = append(queries, term, misspell(rng, term))
+	}
+
+	for _, query := range queries {
+		got := m.Match(query, 1)
+		want := fullScan(m, query, 1)
+		if len(want) == 0 {
+			continue
+		}
+		if len(got) == 0 {
+			t.Errorf("Match(%q) found nothing, full scan found %q (%.3f)", query, want[0].ConceptName, want[0].Score)
+			continue
+		}
+		if got[0].MatchedItem.Name != want[0].ConceptName || got[0].Confidence != want[0].Score {
+			t.Errorf("Match(%q) = %q (%.3f), full scan = %q (%.3f)",
+				query, got[0].MatchedItem.Name, got[0].Confidence, want[0].ConceptName, want[0].Score)
+		}
+	}
+}
+
+// BenchmarkMatcher times matching one counter note, a handful of lines with typical
+// misspellings, against catalogs of 10k and 100k aliases.
+func BenchmarkMatcher(b *testing.B) {
+	for _, size := range []int{10000, 100000} {
+		b.Run(fmt.Sprintf("%dk", size/1000), func(b *testing.B) {
+			kb := syntheticCatalog(size, 1)
+			m := NewMatcher(nil, kb, nil)
+
+			rng := rand.New(rand.NewSource(2))
+			terms := catalogTerms(kb)
+			note := make([]string, 8)
+			for i := range note {
+				note[i] = misspell(rng, terms[rng.Intn(len(terms))])
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, line := range note {
+					m.Match(line, 5)
+				}
+			}
+		})
+	}
+}
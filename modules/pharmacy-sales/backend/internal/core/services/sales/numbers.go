@@ -0,0 +1,117 @@
+package sales
+
+import (
+	"billing-module/internal/core/domain"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// numberWords maps spoken counts, in English and Hindi, to their value.
+// Strengths are never written in words, so these are always quantities.
+var numberWords = map[string]float64{
+	// English
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8,
+	"nine": 9, "ten": 10, "eleven": 11, "twelve": 12, "fifteen": 15, "twenty": 20, "thirty": 30,
+	"half": 0.5, "quarter": 0.25, "dozen": 12,
+	// Hindi (romanised)
+	"ek": 1, "do": 2, "teen": 3, "tin": 3, "char": 4, "chaar": 4, "paanch": 5, "panch": 5,
+	"chhe": 6, "chhah": 6, "che": 6, "saat": 7, "sat": 7, "aath": 8, "aanth": 8, "nau": 9,
+	"das": 10, "dus": 10, "gyarah": 11, "barah": 12, "baarah": 12, "pandrah": 15, "bees": 20, "tees": 30,
+	"aadha": 0.5, "adha": 0.5, "aadhi": 0.5, "adhi": 0.5, "dedh": 1.5, "derh": 1.5, "dhai": 2.5, "dhaai": 2.5,
+	// Hindi (Devanagari)
+	"एक": 1, "दो": 2, "तीन": 3, "चार": 4, "पांच": 5, "पाँच": 5, "दस": 10, "आधा": 0.5, "डेढ़": 1.5, "ढाई": 2.5,
+}
+
+// articles count as one, but only directly before a unit word ("a strip of dolo")
+var articles = map[string]bool{"a": true, "an": true}
+
+// strengthSuffixes mark a number as a strength rather than a count ("500mg", "650 mg")
+var strengthSuffixes = map[string]bool{
+	"mg": true, "mcg": true, "g": true, "gm": true, "ml": true, "iu": true, "%": true,
+}
+
+var (
+	// "500", "1.5", "1/2"
+	numberRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?|\d+/\d+)$`)
+	// "500mg", "2strips", "2x", "x2"
+	attachedNumberRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)([a-z%]+)$`)
+	multiplierRegex     = regexp.MustCompile(`^x(\d+)$`)
+)
+
+// Vocabulary holds the number tokens that appear in known medicine names and aliases,
+// so "pan 40" is read as a strength while "pan 2" is read as a quantity.
+type Vocabulary struct {
+	strengths map[string]bool // "650", "40", "500"
+	phrases   map[string]bool // "dolo 650", "pan 40"
+}
+
+// NewVocabulary indexes the numbers used in the knowledge base and inventory names.
+func NewVocabulary(knowledgeBase map[string][]string, items []domain.Item) *Vocabulary {
+	v := &Vocabulary{
+		strengths: make(map[string]bool),
+		phrases:   make(map[string]bool),
+	}
+
+	for canonical, aliases := range knowledgeBase {
+		v.add(canonical)
+		for _, alias := range aliases {
+			v.add(alias)
+		}
+	}
+	for _, item := range items {
+		v.add(item.Name)
+	}
+	return v
+}
+
+func (v *Vocabulary) add(name string) {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, w := range words {
+		number := w
+		if m := attachedNumberRegex.FindStringSubmatch(w); m != nil && strengthSuffixes[m[2]] {
+			number = m[1]
+		}
+		if _, err := strconv.Atoi(number); err != nil {
+			continue
+		}
+		v.strengths[number] = true
+		if i > 0 {
+			v.phrases[words[i-1]+" "+number] = true
+		}
+	}
+}
+
+// knownPhrase reports whether "word number" appears in a known name, e.g. "dolo 650".
+func (v *Vocabulary) knownPhrase(word, number string) bool {
+	return v != nil && v.phrases[word+" "+number]
+}
+
+// knownStrength reports whether the number is used as a strength anywhere in the knowledge base.
+func (v *Vocabulary) knownStrength(number string) bool {
+	return v != nil && v.strengths[number]
+}
+
+// parseNumber reads a bare numeric token: digits, decimals, fractions or a number word.
+func parseNumber(token string) (float64, bool) {
+	if value, ok := numberWords[token]; ok {
+		return value, true
+	}
+	if !numberRegex.MatchString(token) {
+		return 0, false
+	}
+	if num, den, found := strings.Cut(token, "/"); found {
+		n, _ := strconv.ParseFloat(num, 64)
+		d, _ := strconv.ParseFloat(den, 64)
+		if d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	value, err := strconv.ParseFloat(token, 64)
+	return value, err == nil
+}
@@ -0,0 +1,71 @@
+package sales
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		token string
+		value float64
+		ok    bool
+	}{
+		{"2", 2, true},
+		{"1.5", 1.5, true},
+		{"1/2", 0.5, true},
+		{"3/0", 0, false},
+		{"650mg", 0, false},
+		{"two", 2, true},
+		{"dozen", 12, true},
+		// Hindi words that are also English words or name fragments
+		{"do", 2, true},
+		{"tin", 3, true},
+		{"das", 10, true},
+		{"sat", 7, true},
+		{"dedh", 1.5, true},
+		{"dhai", 2.5, true},
+		{"पाँच", 5, true},
+		{"dolo", 0, false},
+	}
+	for _, tt := range tests {
+		value, ok := parseNumber(tt.token)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, %v", tt.token, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestVocabulary(t *testing.T) {
+	vocab := NewVocabulary(
+		map[string][]string{"Dolo 650": {"dolo-650", "dolo"}, "Augmentin 625 Duo": nil},
+		nil,
+	)
+	tests := []struct {
+		word, number     string
+		phrase, strength bool
+	}{
+		{"dolo", "650", true, true},
+		{"augmentin", "625", true, true},
+		{"crocin", "650", false, true},
+		{"dolo", "500", false, false},
+	}
+	for _, tt := range tests {
+		if got := vocab.knownPhrase(tt.word, tt.number); got != tt.phrase {
+			t.Errorf("knownPhrase(%q, %q) = %v, want %v", tt.word, tt.number, got, tt.phrase)
+		}
+		if got := vocab.knownStrength(tt.number); got != tt.strength {
+			t.Errorf("knownStrength(%q) = %v, want %v", tt.number, got, tt.strength)
+		}
+	}
+
+	// A nil vocabulary knows nothing
+	var none *Vocabulary
+	if none.knownPhrase("dolo", "650") || none.knownStrength("650") {
+		t.Error("nil vocabulary reported a known number")
+	}
+}
+
+func TestVocabularyStrengthSuffix(t *testing.T) {
+	vocab := NewVocabulary(map[string][]string{"Paracetamol 500mg": nil}, nil)
+	if !vocab.knownPhrase("paracetamol", "500") {
+		t.Error(`"500mg" in a name was not indexed as strength 500`)
+	}
+}
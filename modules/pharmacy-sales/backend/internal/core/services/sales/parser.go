@@ -4,50 +4,238 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type ParsedItem struct {
 	OriginalString string
-	LikelyName     string
-	Quantity       int
+	LikelyName     string  // Includes the strength, e.g. "dolo 650"
+	Quantity       float64 // In Unit; may be fractional, e.g. "half strip"
+	Unit           string  // Canonical unit the quantity is expressed in, "" if none was given
+	Strength       string  // Strength tokens found in the line, e.g. "650" or "500mg"
 }
 
-var quantityRegex = regexp.MustCompile(`(\d+)`)
+// Segment is one item-sized piece of a sales note. Start and End are rune offsets
+// into the original note so the UI can highlight the captured text.
+type Segment struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// Separators between items in a note: punctuation, newlines and the joining words "and"/"aur"
+var segmentSeparatorRegex = regexp.MustCompile(`(?i)[,;\r\n]+|\band\b|\baur\b`)
 
-// Stopwords to filter out during parsing
+// Stopwords to filter out during parsing. Unit words are handled separately, see unitWords.
 var stopWords = map[string]bool{
-	"goli": true, "tablet": true, "tablets": true, "strip": true, "patta": true,
-	"pack": true, "pcs": true, "dawa": true, "chaiye": true, "dena": true,
+	"dawa": true, "chaiye": true, "dena": true, "of": true,
+}
+
+// lineToken is a word or number kept from a line while deciding what each number means
+type lineToken struct {
+	text      string
+	isNumber  bool
+	value     float64
+	fromWord  bool // Spelled out ("do", "two"), never a strength
+	strength  bool // Part of the name, e.g. "650" in "dolo 650"
+	explicit  bool // Definitely a quantity, e.g. followed by a unit or written "x2"
+	isDecided bool
 }
 
-func ParseLine(line string) ParsedItem {
+// ParseLine extracts the quantity, unit and likely medicine name from one segment of a note.
+// vocab may be nil, in which case strengths are recognised by suffix ("500mg") and size alone.
+func ParseLine(line string, vocab *Vocabulary) ParsedItem {
 	line = strings.ToLower(line)
-	qty := 1
+	unit := ""
 
-	// Extract quantity
-	matches := quantityRegex.FindStringSubmatch(line)
-	if len(matches) > 1 {
-		if q, err := strconv.Atoi(matches[1]); err == nil {
-			qty = q
+	fields := strings.Fields(line)
+	var tokens []lineToken
+
+	lastNumber := func() *lineToken {
+		if len(tokens) > 0 && tokens[len(tokens)-1].isNumber {
+			return &tokens[len(tokens)-1]
 		}
-		// Remove quantity from line
-		line = quantityRegex.ReplaceAllString(line, "")
+		return nil
 	}
 
-	// Clean up words
-	words := strings.Fields(line)
-	var cleanWords []string
-	for _, w := range words {
-		if !stopWords[w] {
-			cleanWords = append(cleanWords, w)
+	for i, f := range fields {
+		f = strings.Trim(f, ".:()[]'\"")
+		if f == "" {
+			continue
+		}
+		next := ""
+		if i+1 < len(fields) {
+			next = strings.Trim(fields[i+1], ".:()[]'\"")
+		}
+
+		// "2 strips": the unit marks the number before it as the quantity
+		if u := CanonicalUnit(f); u != "" {
+			if unit == "" {
+				unit = u
+			}
+			if n := lastNumber(); n != nil && !n.isDecided {
+				n.explicit, n.isDecided = true, true
+			}
+			continue
+		}
+
+		// "650 mg": the suffix marks the number before it as a strength
+		if strengthSuffixes[f] {
+			if n := lastNumber(); n != nil && !n.fromWord {
+				n.text += f
+				n.strength, n.isDecided = true, true
+			}
+			continue
+		}
+
+		if stopWords[f] {
+			continue
+		}
+
+		if m := attachedNumberRegex.FindStringSubmatch(f); m != nil {
+			value, _ := strconv.ParseFloat(m[1], 64)
+			switch {
+			case strengthSuffixes[m[2]]:
+				tokens = append(tokens, lineToken{text: f, isNumber: true, value: value, strength: true, isDecided: true})
+				continue
+			case CanonicalUnit(m[2]) != "":
+				if unit == "" {
+					unit = CanonicalUnit(m[2])
+				}
+				tokens = append(tokens, lineToken{text: m[1], isNumber: true, value: value, explicit: true, isDecided: true})
+				continue
+			case m[2] == "x":
+				tokens = append(tokens, lineToken{text: m[1], isNumber: true, value: value, explicit: true, isDecided: true})
+				continue
+			}
+		}
+
+		if m := multiplierRegex.FindStringSubmatch(f); m != nil {
+			value, _ := strconv.ParseFloat(m[1], 64)
+			tokens = append(tokens, lineToken{text: m[1], isNumber: true, value: value, explicit: true, isDecided: true})
+			continue
+		}
+
+		if articles[f] && CanonicalUnit(next) != "" {
+			tokens = append(tokens, lineToken{text: f, isNumber: true, value: 1, fromWord: true})
+			continue
+		}
+
+		if value, ok := parseNumber(f); ok {
+			_, fromWord := numberWords[f]
+			tokens = append(tokens, lineToken{text: f, isNumber: true, value: value, fromWord: fromWord})
+			continue
+		}
+
+		tokens = append(tokens, lineToken{text: f})
+	}
+
+	decideNumbers(tokens, vocab)
+
+	// Prefer a quantity that was marked explicitly, otherwise take the first one
+	qty := 1.0
+	qtyFound := false
+	for _, t := range tokens {
+		if t.isNumber && !t.strength && t.explicit {
+			qty, qtyFound = t.value, true
+			break
+		}
+	}
+	if !qtyFound {
+		for _, t := range tokens {
+			if t.isNumber && !t.strength {
+				qty = t.value
+				break
+			}
 		}
 	}
 
-	likelyName := strings.Join(cleanWords, " ")
+	var nameWords, strengths []string
+	for _, t := range tokens {
+		if !t.isNumber {
+			nameWords = append(nameWords, t.text)
+		} else if t.strength {
+			nameWords = append(nameWords, t.text)
+			strengths = append(strengths, t.text)
+		}
+	}
 
 	return ParsedItem{
 		OriginalString: line,
-		LikelyName:     likelyName,
+		LikelyName:     strings.Join(nameWords, " "),
 		Quantity:       qty,
+		Unit:           unit,
+		Strength:       strings.Join(strengths, " "),
 	}
 }
+
+// decideNumbers marks each undecided number as a strength or a quantity. A number
+// directly after a name word is a strength when that pairing exists in the knowledge base
+// ("dolo 650"), or when it is a known strength and another number can serve as the quantity.
+func decideNumbers(tokens []lineToken, vocab *Vocabulary) {
+	candidates := 0
+	for _, t := range tokens {
+		if t.isNumber && !t.strength {
+			candidates++
+		}
+	}
+
+	for i := range tokens {
+		t := &tokens[i]
+		if !t.isNumber || t.isDecided {
+			continue
+		}
+		t.isDecided = true
+
+		prevWord := ""
+		if i > 0 && !tokens[i-1].isNumber {
+			prevWord = tokens[i-1].text
+		}
+
+		switch {
+		case t.fromWord || prevWord == "":
+			// "do dolo", "2 dolo": counts lead or are spelled out
+		case vocab.knownPhrase(prevWord, t.text):
+			t.strength = true
+		case vocab.knownStrength(t.text) && candidates > 1:
+			t.strength = true
+		case t.value >= 100 && t.value == float64(int(t.value)):
+			// Nobody asks for a hundred of something without saying the unit
+			t.strength = true
+		}
+
+		if t.strength {
+			candidates--
+		}
+	}
+}
+
+// SplitNote breaks a free-text note such as "PC 1 strip, Dolo 1 pack" into one segment per item.
+func SplitNote(note string) []Segment {
+	var segments []Segment
+
+	addSegment := func(start, end int) {
+		raw := note[start:end]
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			return
+		}
+		start += strings.Index(raw, trimmed)
+		end = start + len(trimmed)
+		runeStart := utf8.RuneCountInString(note[:start])
+		segments = append(segments, Segment{
+			Text:  trimmed,
+			Start: runeStart,
+			End:   runeStart + utf8.RuneCountInString(trimmed),
+		})
+	}
+
+	prev := 0
+	for _, loc := range segmentSeparatorRegex.FindAllStringIndex(note, -1) {
+		addSegment(prev, loc[0])
+		prev = loc[1]
+	}
+	addSegment(prev, len(note))
+
+	return segments
+}
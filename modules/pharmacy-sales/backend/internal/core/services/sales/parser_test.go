@@ -0,0 +1,146 @@
+package sales
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testVocabulary is a small knowledge base with strengths in its names.
+func testVocabulary() *Vocabulary {
+	return NewVocabulary(map[string][]string{
+		"Dolo 650":   {"dolo"},
+		"Pan 40":     {"pan"},
+		"Crocin 500": {"crocin"},
+	}, nil)
+}
+
+// Counter notes as staff type them, one item per line.
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		noVocab  bool // parse without a knowledge base
+		name     string
+		qty      float64
+		unit     string
+		strength string
+	}{
+		// Strength suffixes and bare strengths
+		{line: "dolo 650", name: "dolo 650", qty: 1, strength: "650"},
+		{line: "dolo 650", noVocab: true, name: "dolo 650", qty: 1, strength: "650"},
+		{line: "dolo 650 2", name: "dolo 650", qty: 2, strength: "650"},
+		{line: "2 dolo 650", name: "dolo 650", qty: 2, strength: "650"},
+		{line: "crocin 500 mg 1 strip", name: "crocin 500mg", qty: 1, unit: UnitStrip, strength: "500mg"},
+		{line: "paracetamol 500mg 10 tab", name: "paracetamol 500mg", qty: 10, unit: UnitTablet, strength: "500mg"},
+		{line: "augmentin 625 x2", name: "augmentin 625", qty: 2, strength: "625"},
+		{line: "3x azithral 500", name: "azithral 500", qty: 3, strength: "500"},
+		{line: "combiflam 200", noVocab: true, name: "combiflam 200", qty: 1, strength: "200"},
+
+		// The knowledge base decides small numbers after a name
+		{line: "pan 40", name: "pan 40", qty: 1, strength: "40"},
+		{line: "pan 40", noVocab: true, name: "pan", qty: 40},
+		{line: "pan 2", name: "pan", qty: 2},
+		{line: "pan 40 2 strips", name: "pan 40", qty: 2, unit: UnitStrip, strength: "40"},
+
+		// Ambiguous Hindi number words are counts, never strengths or name words
+		{line: "do dolo 650", name: "dolo 650", qty: 2, strength: "650"},
+		{line: "dolo 650 do", name: "dolo 650", qty: 2, strength: "650"},
+		{line: "dolo do patta", name: "dolo", qty: 2, unit: UnitStrip},
+		{line: "tin crocin", name: "crocin", qty: 3},
+		{line: "crocin tin strip", name: "crocin", qty: 3, unit: UnitStrip},
+		{line: "das goli pan 40", name: "pan 40", qty: 10, unit: UnitTablet, strength: "40"},
+		{line: "sat pcm 500mg", name: "pcm 500mg", qty: 7, strength: "500mg"},
+
+		// Fractions, articles and attached units
+		{line: "half strip dolo", name: "dolo", qty: 0.5, unit: UnitStrip},
+		{line: "dolo 1/2 strip", name: "dolo", qty: 0.5, unit: UnitStrip},
+		{line: "dedh patta dolo", name: "dolo", qty: 1.5, unit: UnitStrip},
+		{line: "a strip of dolo", name: "dolo", qty: 1, unit: UnitStrip},
+		{line: "calpol 2strips", name: "calpol", qty: 2, unit: UnitStrip},
+		{line: "ek bottle benadryl", name: "benadryl", qty: 1, unit: UnitBottle},
+		{line: "ORS 4 sachets", name: "ors", qty: 4, unit: UnitSachet},
+		{line: "dolo 650 dawa dena", name: "dolo 650", qty: 1, strength: "650"},
+	}
+
+	vocab := testVocabulary()
+	for _, tt := range tests {
+		v := vocab
+		if tt.noVocab {
+			v = nil
+		}
+		got := ParseLine(tt.line, v)
+		if got.LikelyName != tt.name || got.Quantity != tt.qty || got.Unit != tt.unit || got.Strength != tt.strength {
+			t.Errorf("ParseLine(%q, vocab=%v) = name %q qty %v unit %q strength %q, want name %q qty %v unit %q strength %q",
+				tt.line, v != nil, got.LikelyName, got.Quantity, got.Unit, got.Strength, tt.name, tt.qty, tt.unit, tt.strength)
+		}
+	}
+}
+
+func TestDecideNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokens   []lineToken
+		strength []bool // per token, after deciding
+	}{
+		{
+			name:     "leading count",
+			tokens:   []lineToken{{text: "2", isNumber: true, value: 2}, {text: "dolo"}},
+			strength: []bool{false, false},
+		},
+		{
+			name:     "known phrase",
+			tokens:   []lineToken{{text: "pan"}, {text: "40", isNumber: true, value: 40}},
+			strength: []bool{false, true},
+		},
+		{
+			name:     "known strength with another count",
+			tokens:   []lineToken{{text: "pantop"}, {text: "40", isNumber: true, value: 40}, {text: "2", isNumber: true, value: 2}},
+			strength: []bool{false, true, false},
+		},
+		{
+			name:     "known strength as the only number",
+			tokens:   []lineToken{{text: "pantop"}, {text: "40", isNumber: true, value: 40}},
+			strength: []bool{false, false},
+		},
+		{
+			name:     "large whole number",
+			tokens:   []lineToken{{text: "combiflam"}, {text: "400", isNumber: true, value: 400}},
+			strength: []bool{false, true},
+		},
+		{
+			name:     "spelled out after a name",
+			tokens:   []lineToken{{text: "dolo"}, {text: "do", isNumber: true, value: 2, fromWord: true}},
+			strength: []bool{false, false},
+		},
+	}
+
+	vocab := testVocabulary()
+	for _, tt := range tests {
+		decideNumbers(tt.tokens, vocab)
+		got := make([]bool, len(tt.tokens))
+		for i, tok := range tt.tokens {
+			got[i] = tok.strength
+		}
+		if !reflect.DeepEqual(got, tt.strength) {
+			t.Errorf("%s: strengths = %v, want %v", tt.name, got, tt.strength)
+		}
+	}
+}
+
+func TestSplitNote(t *testing.T) {
+	tests := []struct {
+		note string
+		want []Segment
+	}{
+		{"PC 1 strip, Dolo 1 pack", []Segment{{"PC 1 strip", 0, 10}, {"Dolo 1 pack", 12, 23}}},
+		{"dolo aur crocin and pan 40", []Segment{{"dolo", 0, 4}, {"crocin", 9, 15}, {"pan 40", 20, 26}}},
+		{"dolo\n\ncrocin;", []Segment{{"dolo", 0, 4}, {"crocin", 6, 12}}},
+		{"दो पत्ता डोलो, crocin", []Segment{{"दो पत्ता डोलो", 0, 13}, {"crocin", 15, 21}}},
+		// "and" inside a word is not a separator
+		{"candid cream", []Segment{{"candid cream", 0, 12}}},
+	}
+	for _, tt := range tests {
+		if got := SplitNote(tt.note); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitNote(%q) = %v, want %v", tt.note, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package sales
+
+import (
+	"strings"
+	"unicode"
+)
+
+// phoneticKey reduces a name to a consonant skeleton, Soundex style, tuned for the way
+// Indian English and Hinglish spellings drift: vowels are dropped after the first letter,
+// aspirates fold into their plain consonant (kh, th, dh, bh), c becomes s or k by sound,
+// z and j merge, v and w merge, and doubled letters collapse. So "paracetamol" and
+// "parasitamol" both become "prstml", and "cough" and "kuff" both become "kf".
+// Words containing digits ("650", "500mg") are kept as they are.
+func phoneticKey(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	keys := make([]string, 0, len(words))
+	for _, w := range words {
+		if strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			keys = append(keys, w)
+			continue
+		}
+		if k := phoneticWord(w); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
+func phoneticWord(word string) string {
+	// A trailing "gh" is an f sound in English spellings: cough, tough
+	if strings.HasSuffix(word, "gh") {
+		word = strings.TrimSuffix(word, "gh") + "f"
+	}
+
+	r := []rune(word)
+	out := make([]rune, 0, len(r))
+	emit := func(code rune) {
+		if len(out) > 0 && out[len(out)-1] == code {
+			return
+		}
+		out = append(out, code)
+	}
+
+	for i := 0; i < len(r); i++ {
+		c := r[i]
+		next := rune(0)
+		if i+1 < len(r) {
+			next = r[i+1]
+		}
+
+		switch c {
+		case 'a', 'e', 'i', 'o', 'u', 'y':
+			// Keep one vowel marker at the start so "azee" and "zee" stay apart
+			if i == 0 {
+				emit('a')
+			}
+		case 'h':
+			// Silent or already folded into the consonant before it
+		case 'p':
+			if next == 'h' {
+				i++
+				emit('f')
+			} else {
+				emit('p')
+			}
+		case 'c':
+			switch next {
+			case 'h':
+				i++
+				emit('c')
+			case 'e', 'i', 'y':
+				emit('s')
+			case 'k':
+				i++
+				emit('k')
+			default:
+				emit('k')
+			}
+		case 'k', 'q':
+			emit('k')
+		case 'x':
+			emit('k')
+			emit('s')
+		case 'w', 'v':
+			emit('v')
+		case 'z', 'j':
+			emit('j')
+		default:
+			if unicode.IsLetter(c) {
+				emit(c)
+			}
+		}
+	}
+	return string(out)
+}
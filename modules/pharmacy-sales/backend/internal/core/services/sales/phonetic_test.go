@@ -0,0 +1,144 @@
+package sales
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPhoneticKey(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"paracetamol", "prstml"},
+		{"parasitamol", "prstml"},
+		{"cough", "kf"},
+		{"kuff", "kf"},
+		{"zincovit", "jnkvt"},
+		{"jincovit", "jnkvt"},
+		{"volini", "vln"},
+		{"wolini", "vln"},
+		{"metformin", "mtfrmn"},
+		{"metphormin", "mtfrmn"},
+		{"bekosules", "bksls"},
+		{"becosules", "bksls"},
+		{"azee", "aj"},
+		{"zee", "j"},
+		{"dolo 650", "dl 650"},
+		{"Pan-40 tab", "pn 40 tb"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := phoneticKey(tt.text); got != tt.want {
+			t.Errorf("phoneticKey(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// The blend is editWeight of the spelling score plus phoneticWeight of the sound score,
+// used only when it beats the spelling alone, plus 0.2 for a prefix.
+func TestScoreTerm(t *testing.T) {
+	tests := []struct {
+		query, term string
+		want        float64
+	}{
+		{"parasitamol", "paracetamol", 0.6*(1-2.0/11) + 0.4},     // sounds the same
+		{"kuff", "cough", 0.6*(1-4.0/5) + 0.4},                   // spelt nothing alike
+		{"dolo", "dolo", 1.2},                                    // exact, and a prefix
+		{"pan", "pantocid", 0.6*(1-5.0/8) + 0.4*(1-3.0/5) + 0.2}, // prefix
+		{"crosin", "crocin", 0.6*(1-1.0/6) + 0.4},                // one letter, same sound
+		{"omex", "omez", 1 - 1.0/4},                              // blend would not help
+	}
+	for _, tt := range tests {
+		got := scoreTerm(tt.query, phoneticKey(tt.query), tt.term, phoneticKey(tt.term))
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("scoreTerm(%q, %q) = %.4f, want %.4f", tt.query, tt.term, got, tt.want)
+		}
+	}
+}
+
+// hinglishKnowledgeBase holds items commonly asked for at the counter.
+var hinglishKnowledgeBase = map[string][]string{
+	"Paracetamol 500mg": {"pcm", "paracetamol", "calpol"},
+	"Crocin Advance":    {"crocin"},
+	"Dolo 650":          {"dolo"},
+	"Combiflam":         {"combiflam"},
+	"Azithromycin 500":  {"azithral", "azithromycin"},
+	"Amoxicillin 500":   {"amoxicillin", "mox"},
+	"Cetirizine 10mg":   {"cetirizine", "cetzine"},
+	"Ciprofloxacin 500": {"ciprofloxacin", "cipro"},
+	"Pantoprazole 40":   {"pantoprazole", "pan 40", "pantocid"},
+	"Omeprazole 20":     {"omeprazole", "omez"},
+	"Benadryl Syrup":    {"benadryl", "cough syrup"},
+	"Vicks Vaporub":     {"vicks"},
+	"Zincovit":          {"zincovit"},
+	"Digene":            {"digene"},
+	"Ondansetron 4mg":   {"ondansetron", "emeset"},
+	"Montelukast 10mg":  {"montelukast", "montair"},
+	"Metformin 500":     {"metformin", "glycomet"},
+	"Eno":               {"eno"},
+	"Disprin":           {"disprin"},
+	"Volini Gel":        {"volini"},
+	"Liv 52":            {"liv 52"},
+	"Becosules":         {"becosules"},
+	"Sinarest":          {"sinarest"},
+	"Allegra 120":       {"allegra"},
+}
+
+// Misspellings as they turn up on counter notes, with the item and confidence the matcher
+// gives them today. A change to the spelling and sound weights shows up here first.
+func TestHinglishMisspellings(t *testing.T) {
+	tests := []struct {
+		query      string
+		want       string
+		confidence float64
+	}{
+		{"parasitamol", "Paracetamol 500mg", 0.891},
+		{"paracitamol", "Paracetamol 500mg", 0.945},
+		{"crosin", "Crocin Advance", 0.900},
+		{"krocin", "Crocin Advance", 0.900},
+		{"dollo", "Dolo 650", 0.880},
+		{"combiflem", "Combiflam", 0.933},
+		{"kombiflam", "Combiflam", 0.933},
+		{"azitromycin", "Azithromycin 500", 0.950},
+		{"azithromysin", "Azithromycin 500", 0.950},
+		{"amoxycillin", "Amoxicillin 500", 0.945},
+		{"amoxicilin", "Amoxicillin 500", 0.945},
+		{"cetrizine", "Cetirizine 10mg", 0.940},
+		{"setrizine", "Cetirizine 10mg", 0.880},
+		{"siprofloxasin", "Ciprofloxacin 500", 0.908},
+		{"pantoprazol", "Pantoprazole 40", 1.150},
+		{"omiprazole", "Omeprazole 20", 0.940},
+		{"benadril", "Benadryl Syrup", 0.925},
+		{"bennadryl", "Benadryl Syrup", 0.933},
+		{"kof syrup", "Benadryl Syrup", 0.782},
+		{"cough sirap", "Benadryl Syrup", 0.891},
+		{"viks", "Vicks Vaporub", 0.880},
+		{"jincovit", "Zincovit", 0.925},
+		{"dijeen", "Digene", 0.567},
+		{"ondensetron", "Ondansetron 4mg", 0.945},
+		{"montilucast", "Montelukast 10mg", 0.891},
+		{"metphormin", "Metformin 500", 0.880},
+		{"ino", "Eno", 0.800},
+		{"dispirin", "Disprin", 0.925},
+		{"wolini", "Volini Gel", 0.900},
+		{"leev 52", "Liv 52", 0.829},
+		{"bekosules", "Becosules", 0.933},
+		{"cinarest", "Sinarest", 0.925},
+		{"alegra", "Allegra 120", 0.914},
+	}
+
+	m := NewMatcher(nil, hinglishKnowledgeBase, nil)
+	for _, tt := range tests {
+		matches := m.Match(tt.query, 1)
+		if len(matches) == 0 {
+			t.Errorf("Match(%q) found nothing, want %q", tt.query, tt.want)
+			continue
+		}
+		got := matches[0]
+		if got.MatchedItem.Name != tt.want || math.Abs(got.Confidence-tt.confidence) > 0.0005 {
+			t.Errorf("Match(%q) = %q (%.3f), want %q (%.3f)",
+				tt.query, got.MatchedItem.Name, got.Confidence, tt.want, tt.confidence)
+		}
+	}
+}
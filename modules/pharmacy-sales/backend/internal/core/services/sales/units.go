@@ -0,0 +1,72 @@
+package sales
+
+import (
+	"billing-module/internal/core/domain"
+	"math"
+	"strings"
+)
+
+// Canonical units the parser understands
+const (
+	UnitTablet  = "tablet"
+	UnitCapsule = "capsule"
+	UnitStrip   = "strip"
+	UnitPack    = "pack"
+	UnitBottle  = "bottle"
+	UnitSachet  = "sachet"
+	UnitTube    = "tube"
+	UnitVial    = "vial"
+	UnitPiece   = "piece"
+)
+
+// unitWords maps the English and Hindi (romanised and Devanagari) words used at the
+// counter to a canonical unit.
+var unitWords = map[string]string{
+	// Tablets
+	"tablet": UnitTablet, "tablets": UnitTablet, "tab": UnitTablet, "tabs": UnitTablet,
+	"goli": UnitTablet, "goliyan": UnitTablet, "goliya": UnitTablet, "गोली": UnitTablet, "गोलियां": UnitTablet,
+	// Capsules
+	"capsule": UnitCapsule, "capsules": UnitCapsule, "cap": UnitCapsule, "caps": UnitCapsule, "kaipsul": UnitCapsule,
+	// Strips
+	"strip": UnitStrip, "strips": UnitStrip, "patta": UnitStrip, "patte": UnitStrip, "pattaa": UnitStrip,
+	"pata": UnitStrip, "पत्ता": UnitStrip, "पत्ते": UnitStrip,
+	// Packs and boxes
+	"pack": UnitPack, "packs": UnitPack, "packet": UnitPack, "packets": UnitPack, "box": UnitPack, "boxes": UnitPack,
+	"dabba": UnitPack, "dibba": UnitPack, "dabbi": UnitPack, "डब्बा": UnitPack, "डिब्बा": UnitPack,
+	// Bottles
+	"bottle": UnitBottle, "bottles": UnitBottle, "botal": UnitBottle, "bottal": UnitBottle, "shishi": UnitBottle,
+	"बोतल": UnitBottle, "शीशी": UnitBottle,
+	// Sachets
+	"sachet": UnitSachet, "sachets": UnitSachet, "pudiya": UnitSachet, "puriya": UnitSachet, "पुड़िया": UnitSachet,
+	// Tubes and vials
+	"tube": UnitTube, "tubes": UnitTube, "vial": UnitVial, "vials": UnitVial,
+	// Loose pieces
+	"pcs": UnitPiece, "piece": UnitPiece, "pieces": UnitPiece, "nos": UnitPiece,
+}
+
+// packUnits hold several base units; how many is declared by Item.PackSize
+var packUnits = map[string]bool{
+	UnitStrip: true,
+	UnitPack:  true,
+}
+
+// CanonicalUnit returns the canonical unit for a word, or "" if it is not a unit.
+func CanonicalUnit(word string) string {
+	return unitWords[strings.ToLower(strings.TrimSpace(word))]
+}
+
+// NormalizeQuantity converts a quantity expressed in the parsed unit into the item's base Unit.
+// A pack unit (strip, pack) is multiplied by the item's PackSize; anything that cannot be
+// converted is kept as is. The result is rounded to whole base units, at least one.
+func NormalizeQuantity(qty float64, unit string, item domain.Item) int {
+	baseUnit := CanonicalUnit(item.Unit)
+	if unit != "" && unit != baseUnit && packUnits[unit] && !packUnits[baseUnit] && item.PackSize > 0 {
+		qty *= float64(item.PackSize)
+	}
+
+	normalized := int(math.Round(qty))
+	if normalized < 1 && qty > 0 {
+		normalized = 1
+	}
+	return normalized
+}
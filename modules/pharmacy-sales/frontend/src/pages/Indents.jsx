@@ -101,6 +101,7 @@ const Indents = () => {
             PENDING: "bg-amber-100 text-amber-700",
             PROCESSING: "bg-blue-100 text-blue-700",
             DISPATCHED: "bg-purple-100 text-purple-700",
+            PARTIALLY_DISPATCHED: "bg-orange-100 text-orange-700",
             FULFILLED: "bg-emerald-100 text-emerald-700",
             APPROVED: "bg-emerald-100 text-emerald-700", // Fallback if still used
             REJECTED: "bg-red-100 text-red-700"
@@ -225,11 +226,16 @@ const Indents = () => {
                                 {indents.map((indent) => (
                                     <tr key={indent.id} className="hover:bg-slate-50/50 transition-colors">
                                         <td className="px-6 py-4 font-semibold text-slate-800">{indent.item_name}</td>
-                                        <td className="px-6 py-4 font-mono text-slate-600">{indent.quantity}</td>
+                                        <td className="px-6 py-4 font-mono text-slate-600">
+                                            {indent.quantity}
+                                            {indent.status === 'PARTIALLY_DISPATCHED' && (
+                                                <div className="text-xs text-orange-600">{indent.dispatched_quantity} sent, rest on backorder</div>
+                                            )}
+                                        </td>
                                         <td className="px-6 py-4 text-slate-500">{new Date(indent.created_at).toLocaleString()}</td>
                                         <td className="px-6 py-4 flex items-center justify-between gap-4">
                                             <StatusBadge status={indent.status} />
-                                            {indent.status === 'DISPATCHED' && (
+                                            {(indent.status === 'DISPATCHED' || indent.status === 'PARTIALLY_DISPATCHED') && (
                                                 <button
                                                     onClick={async () => {
                                                         try {